@@ -0,0 +1,221 @@
+package goutte
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Internal invariant violations detected by Verify or the background auditor.
+type IntegrityError struct {
+	Problems []string
+}
+
+func (e *IntegrityError) Error() string {
+	return "goutte: cache integrity violated: " + strings.Join(e.Problems, "; ")
+}
+
+// Checks every internal invariant of the cache under the lock: map and list consistency,
+// expiration heap ordering and indexes, rank tracker accounting, and the totals kept alongside the
+// entries: weight, tombstone count and eviction policy segments.
+// It returns an *IntegrityError describing all violations found, or nil.
+// This is O(n) in the number of entries; see WithAuditor for incremental checking on live caches.
+func (c *Cache[K, V]) Verify() error {
+	c.lock()
+	defer c.unlock()
+
+	problems := c.checkGlobalLocked()
+	for ele := c.ll.Front(); ele != nil; ele = ele.Next() {
+		problems = append(problems, c.checkElementLocked(ele)...)
+	}
+	problems = append(problems, c.checkTotalsLocked()...)
+	for i := range c.expHeap {
+		problems = append(problems, c.checkHeapSlotLocked(i)...)
+	}
+	if len(problems) > 0 {
+		return &IntegrityError{Problems: problems}
+	}
+	return nil
+}
+
+// Checks invariants that cost O(1) to verify.
+func (c *Cache[K, V]) checkGlobalLocked() []string {
+	var problems []string
+	if len(c.cache) != c.ll.Len() {
+		problems = append(problems, fmt.Sprintf("map holds %d keys but list holds %d elements", len(c.cache), c.ll.Len()))
+	}
+	if c.policy.kind == policyRandom && len(c.slots) != c.ll.Len() {
+		problems = append(problems, fmt.Sprintf("random policy holds %d slots but list holds %d elements", len(c.slots), c.ll.Len()))
+	}
+	if c.ranks != nil {
+		total := c.ranks.tail
+		for _, n := range c.ranks.counts {
+			total += n
+		}
+		if total != c.ll.Len() {
+			problems = append(problems, fmt.Sprintf("rank tracker accounts for %d entries but list holds %d", total, c.ll.Len()))
+		}
+	}
+	return problems
+}
+
+// Checks the totals maintained incrementally against the entries they account for. Unlike the other
+// checks, it needs a full walk of the list, so the auditor skips it.
+func (c *Cache[K, V]) checkTotalsLocked() []string {
+	var problems []string
+	var weight int64
+	tombstones, admitted := 0, 0
+	inAdmission := false // past the head of the admission queue
+	for ele := c.ll.Front(); ele != nil; ele = ele.Next() {
+		ent := ele.Value.(*entry[K, V])
+		weight += ent.weight
+		if ent.tombstone {
+			tombstones++
+		}
+		if ele == c.segs.head {
+			inAdmission = true
+		}
+		if ent.admitted {
+			admitted++
+		}
+		if ent.admitted != inAdmission {
+			problems = append(problems, fmt.Sprintf("key %v is on the wrong side of the admission queue head", ent.key))
+		}
+	}
+	if weight != c.weight {
+		problems = append(problems, fmt.Sprintf("entries weigh %d but the total weight is %d", weight, c.weight))
+	}
+	if tombstones != c.tombstones {
+		problems = append(problems, fmt.Sprintf("list holds %d tombstones but %d are counted", tombstones, c.tombstones))
+	}
+	if admitted != c.segs.n {
+		problems = append(problems, fmt.Sprintf("admission queue holds %d entries but %d are counted", admitted, c.segs.n))
+	}
+	if c.segs.head != nil && !inAdmission {
+		problems = append(problems, "admission queue head is not in the list")
+	}
+	return problems
+}
+
+// Checks that a list element is indexed by the map and that its expiration entry is consistent.
+func (c *Cache[K, V]) checkElementLocked(ele *list.Element) []string {
+	var problems []string
+	ent := ele.Value.(*entry[K, V])
+	if c.cache[ent.key] != ele {
+		problems = append(problems, fmt.Sprintf("key %v in list is not mapped to its element", ent.key))
+	}
+	if c.policy.kind == policyRandom && (ent.slot < 0 || ent.slot >= len(c.slots) || c.slots[ent.slot] != ele) {
+		problems = append(problems, fmt.Sprintf("key %v is missing from the slots of the random policy", ent.key))
+	}
+	if c.wheel != nil {
+		switch {
+		case ent.expiration.IsZero() != (ent.timer == nil):
+			problems = append(problems, fmt.Sprintf("key %v expiration is out of sync with its wheel timer", ent.key))
+		case ent.timer != nil && (ent.timer.slot == nil || ent.timer.key != ent.key):
+			problems = append(problems, fmt.Sprintf("key %v has a wheel timer that is not scheduled", ent.key))
+		}
+		return problems
+	}
+	if ent.exp == nil {
+		return problems
+	}
+	i := ent.exp.index
+	switch {
+	case i < 0 || i >= len(c.expHeap) || c.expHeap[i] != ent.exp:
+		problems = append(problems, fmt.Sprintf("key %v has an expiration entry missing from the heap", ent.key))
+	case ent.exp.canceled:
+		problems = append(problems, fmt.Sprintf("key %v has a canceled expiration entry", ent.key))
+	case !ent.exp.expiration.Equal(ent.expiration):
+		problems = append(problems, fmt.Sprintf("key %v expiration differs from its heap entry", ent.key))
+	}
+	return problems
+}
+
+// Checks the heap slot at index i: its index field, heap ordering against its parent and its owner.
+func (c *Cache[K, V]) checkHeapSlotLocked(i int) []string {
+	var problems []string
+	e := c.expHeap[i]
+	if e.index != i {
+		problems = append(problems, fmt.Sprintf("heap slot %d holds an entry indexed %d", i, e.index))
+	}
+	if i > 0 && e.expiration.Before(c.expHeap[(i-1)/2].expiration) {
+		problems = append(problems, fmt.Sprintf("heap slot %d expires before its parent", i))
+	}
+	if e.canceled {
+		return problems
+	}
+	ele, ok := c.cache[e.key]
+	if !ok || ele.Value.(*entry[K, V]).exp != e {
+		problems = append(problems, fmt.Sprintf("heap slot %d is live but not owned by key %v", i, e.key))
+	}
+	return problems
+}
+
+// Incremental auditor state: cursors into the list and the heap, advanced one batch per tick.
+type auditor struct {
+	interval time.Duration
+	batch    int
+	report   func(error)
+	listPos  *list.Element
+	heapPos  int
+}
+
+// Starts a low-priority goroutine that verifies internal invariants of the live cache incrementally.
+// Every interval it checks the O(1) invariants plus the next batch of list elements and heap slots,
+// holding the lock only for that batch, and cycles through the whole cache over successive ticks.
+// Violations are passed to report as an *IntegrityError, outside the lock.
+func WithAuditor[K comparable, V any](interval time.Duration, batch int, report func(error)) Option[K, V] {
+	if interval <= 0 || batch <= 0 {
+		panic("auditor interval and batch must be greater than zero")
+	}
+	return func(c *Cache[K, V]) {
+		c.audit = &auditor{interval: interval, batch: batch, report: report}
+	}
+}
+
+func (c *Cache[K, V]) auditProcessor() {
+	ticker := time.NewTicker(c.audit.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.done:
+			return
+		}
+		if problems := c.auditStep(); len(problems) > 0 {
+			c.audit.report(&IntegrityError{Problems: problems})
+		}
+	}
+}
+
+// Checks the next batch of list elements and heap slots.
+func (c *Cache[K, V]) auditStep() []string {
+	c.lock()
+	defer c.unlock()
+
+	a := c.audit
+	problems := c.checkGlobalLocked()
+
+	// Restart the walk from the front once it completes, or if the element at the cursor was removed
+	// since the last step: it would fail the checks, and has no successor anyway.
+	ele := a.listPos
+	if ele == nil || c.cache[ele.Value.(*entry[K, V]).key] != ele {
+		ele = c.ll.Front()
+	}
+	for n := 0; n < a.batch && ele != nil; n++ {
+		problems = append(problems, c.checkElementLocked(ele)...)
+		ele = ele.Next()
+	}
+	a.listPos = ele
+
+	if a.heapPos >= len(c.expHeap) {
+		a.heapPos = 0
+	}
+	for n := 0; n < a.batch && a.heapPos < len(c.expHeap); n++ {
+		problems = append(problems, c.checkHeapSlotLocked(a.heapPos)...)
+		a.heapPos++
+	}
+	return problems
+}
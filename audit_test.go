@@ -0,0 +1,137 @@
+package goutte_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheVerify(t *testing.T) {
+	cache := goutte.NewCache[int, int](50, goutte.WithRankHistogram[int, int]())
+	defer cache.Close()
+
+	for i := 0; i < 200; i++ {
+		if i%3 == 0 {
+			cache.SetWithTTL(i, i, time.Duration(i%7)*time.Millisecond)
+		} else {
+			cache.Set(i, i)
+		}
+		cache.Get(i / 2)
+		if i%5 == 0 {
+			cache.Delete(i - 1)
+		}
+	}
+
+	if err := cache.Verify(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCacheAuditor(t *testing.T) {
+	var mu sync.Mutex
+	var reported []error
+	report := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, err)
+	}
+
+	cache := goutte.NewCache[int, int](100, goutte.WithAuditor[int, int](time.Millisecond, 10, report))
+	defer cache.Close()
+
+	deadline := time.Now().Add(50 * time.Millisecond)
+	for i := 0; time.Now().Before(deadline); i++ {
+		cache.SetWithTTL(i%300, i, time.Duration(i%20)*time.Millisecond)
+		cache.Get(i % 150)
+		if i%1000 == 0 {
+			cache.Dump()
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, err := range reported {
+		t.Error(err)
+	}
+}
+
+func TestCacheAuditorRemovedCursor(t *testing.T) {
+	var mu sync.Mutex
+	var reported []error
+	report := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, err)
+	}
+
+	// With a batch of 1, the cursor rests on an element that the writes below keep deleting.
+	cache := goutte.NewCache[int, int](4, goutte.WithAuditor[int, int](time.Millisecond, 1, report))
+	defer cache.Close()
+	for i := range 4 {
+		cache.Set(i, i)
+	}
+
+	deadline := time.Now().Add(50 * time.Millisecond)
+	for i := 0; time.Now().Before(deadline); i++ {
+		cache.Delete(i % 4)
+		time.Sleep(100 * time.Microsecond)
+		cache.Set(i%4, i)
+	}
+
+	if err := cache.Verify(); err != nil {
+		t.Error(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, err := range reported {
+		t.Error(err)
+	}
+}
+
+func TestCacheVerifyTotals(t *testing.T) {
+	policies := []goutte.Policy{
+		goutte.PolicyLRU(),
+		goutte.Policy2Q(0.25, 0.5),
+		goutte.PolicySLRU(0.8),
+		goutte.PolicyClock(),
+		goutte.PolicyFIFO(),
+		goutte.PolicyRandom(),
+		goutte.PolicyMRU(),
+	}
+	for _, p := range policies {
+		t.Run(p.String(), func(t *testing.T) {
+			cache := goutte.NewCache(50,
+				goutte.WithPolicy[int, int](p),
+				goutte.WithWeigher[int, int](200, func(_, v int) int64 { return int64(v % 9) }))
+			defer cache.Close()
+
+			for i := 0; i < 500; i++ {
+				switch i % 6 {
+				case 0:
+					cache.SetWithCost(i%120, i, int64(i%13), 0)
+				case 1:
+					cache.SoftDelete((i-1)%120, time.Minute)
+				case 2:
+					cache.Resurrect((i - 8) % 120)
+				case 3:
+					cache.Replace(i%120, i)
+				default:
+					cache.Set(i%120, i)
+				}
+				cache.Get(i % 60)
+				if i%97 == 0 {
+					cache.Delete(i % 120)
+				}
+				if i%50 == 0 {
+					cache.SetCapacity(30 + i%40)
+				}
+			}
+
+			if err := cache.Verify(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
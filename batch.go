@@ -0,0 +1,106 @@
+package goutte
+
+import "time"
+
+// Request-scoped view of a Cache that serves repeated reads from a local copy and buffers writes
+// until Flush, which applies them to the shared cache under a single lock acquisition.
+// The first Get of a key reads through to the cache; later Gets of the same key, including misses,
+// are answered locally, and see the writes buffered in the view. Writes are not visible to other
+// users of the cache until Flush. A BatchView is not safe for concurrent use.
+type BatchView[K comparable, V any] struct {
+	cache   *Cache[K, V]
+	reads   map[K]batchRead[V]
+	pending map[K]int // index in ops of the buffered write of each key
+	ops     []batchOp[K, V]
+}
+
+type batchRead[V any] struct {
+	value V
+	ok    bool
+}
+
+// Buffered write; a delete if del is set.
+type batchOp[K comparable, V any] struct {
+	key   K
+	value V
+	ttl   time.Duration
+	del   bool
+
+	defaultTTL bool // use the default TTL of the cache instead of ttl
+}
+
+// Returns a new, empty BatchView of the cache, typically one per request.
+func (c *Cache[K, V]) Batch() *BatchView[K, V] {
+	return &BatchView[K, V]{
+		cache:   c,
+		reads:   make(map[K]batchRead[V]),
+		pending: make(map[K]int),
+	}
+}
+
+// Retrieves a value, reading through to the cache only the first time the key is requested.
+func (b *BatchView[K, V]) Get(key K) (V, bool) {
+	if r, ok := b.reads[key]; ok {
+		return r.value, r.ok
+	}
+	value, ok := b.cache.Get(key)
+	b.reads[key] = batchRead[V]{value: value, ok: ok}
+	return value, ok
+}
+
+// Buffers an insert or update with the default TTL of the cache, if any.
+func (b *BatchView[K, V]) Set(key K, value V) {
+	b.record(batchOp[K, V]{key: key, value: value, defaultTTL: true})
+	b.reads[key] = batchRead[V]{value: value, ok: true}
+}
+
+// Buffers an insert or update with an optional TTL, counted from Flush, overriding the default TTL.
+func (b *BatchView[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	b.record(batchOp[K, V]{key: key, value: value, ttl: ttl})
+	b.reads[key] = batchRead[V]{value: value, ok: true}
+}
+
+// Buffers the removal of a key.
+func (b *BatchView[K, V]) Delete(key K) {
+	b.record(batchOp[K, V]{key: key, del: true})
+	b.reads[key] = batchRead[V]{}
+}
+
+// Keeps only the last buffered write of each key, at the position of its first write.
+func (b *BatchView[K, V]) record(op batchOp[K, V]) {
+	if i, ok := b.pending[op.key]; ok {
+		b.ops[i] = op
+		return
+	}
+	b.pending[op.key] = len(b.ops)
+	b.ops = append(b.ops, op)
+}
+
+// Applies the buffered writes to the cache under a single lock acquisition, in the order their keys
+// were first written, and empties the view. It returns the number of writes applied.
+func (b *BatchView[K, V]) Flush() int {
+	ops := b.ops
+	b.ops = nil
+	b.reads = make(map[K]batchRead[V])
+	b.pending = make(map[K]int)
+	if len(ops) == 0 {
+		return 0
+	}
+
+	c := b.cache
+	c.lock()
+	defer c.unlock()
+
+	for _, op := range ops {
+		if op.del {
+			c.deleteLocked(op.key)
+			continue
+		}
+		ttl := op.ttl
+		if op.defaultTTL {
+			ttl = c.defaultTTL
+		}
+		c.writeLocked(op.key, op.value, ttl)
+	}
+	return len(ops)
+}
@@ -0,0 +1,56 @@
+package goutte_test
+
+import (
+	"testing"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestBatchView(t *testing.T) {
+	cache := goutte.NewCache[string, int](4, goutte.WithKeyStats[string, int](4, nil))
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+
+	batch := cache.Batch()
+	for i := 0; i < 3; i++ {
+		if val, ok := batch.Get("a"); !ok || val != 1 {
+			t.Errorf("Expected batch to read 'a' as 1, got %d (found: %v)", val, ok)
+		}
+	}
+	if top := cache.TopKeys(); len(top) != 1 || top[0].Hits != 1 {
+		t.Errorf("Expected repeated Gets to hit the cache once, got %+v", top)
+	}
+
+	batch.Set("a", 10)
+	batch.Set("c", 3)
+	batch.Set("a", 11)
+	batch.Delete("b")
+
+	// Buffered writes are visible through the view only.
+	if val, ok := batch.Get("a"); !ok || val != 11 {
+		t.Errorf("Expected batch to read its own write of 'a', got %d (found: %v)", val, ok)
+	}
+	if _, ok := batch.Get("b"); ok {
+		t.Error("Expected batch to see its own delete of 'b'")
+	}
+	if val, _ := cache.Get("a"); val != 1 {
+		t.Errorf("Expected the cache to be untouched before Flush, got 'a' = %d", val)
+	}
+
+	if n := batch.Flush(); n != 3 {
+		t.Errorf("Expected 3 deduplicated writes to be flushed, got %d", n)
+	}
+	if val, ok := cache.Get("a"); !ok || val != 11 {
+		t.Errorf("Expected 'a' to be 11 after Flush, got %d (found: %v)", val, ok)
+	}
+	if val, ok := cache.Get("c"); !ok || val != 3 {
+		t.Errorf("Expected 'c' to be 3 after Flush, got %d (found: %v)", val, ok)
+	}
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected 'b' to be deleted after Flush")
+	}
+	if n := batch.Flush(); n != 0 {
+		t.Errorf("Expected an empty second Flush, got %d writes", n)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"github.com/dgraph-io/ristretto/v2"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/maypok86/otter/v2"
+	"github.com/shellkah/goutte"
+)
+
+// Common surface of the benchmarked caches.
+type cache interface {
+	Get(key uint64) (uint64, bool)
+	Set(key, value uint64)
+	Close()
+}
+
+// Implemented by caches that apply writes asynchronously, so hit ratio replays can wait for them.
+type waiter interface {
+	Wait()
+}
+
+// Named constructor for a benchmarked cache.
+type contender struct {
+	name string
+	new  func(capacity int) cache
+}
+
+var contenders = []contender{
+	{"goutte", newGoutte},
+	{"golang-lru", newGolangLRU},
+	{"ristretto", newRistretto},
+	{"otter", newOtter},
+}
+
+type goutteCache struct {
+	c *goutte.Cache[uint64, uint64]
+}
+
+func newGoutte(capacity int) cache {
+	return goutteCache{goutte.NewCache[uint64, uint64](capacity)}
+}
+
+func (g goutteCache) Get(key uint64) (uint64, bool) { return g.c.Get(key) }
+func (g goutteCache) Set(key, value uint64)         { g.c.Set(key, value) }
+func (g goutteCache) Close()                        { g.c.Close() }
+
+type golangLRUCache struct {
+	c *lru.Cache[uint64, uint64]
+}
+
+func newGolangLRU(capacity int) cache {
+	c, err := lru.New[uint64, uint64](capacity)
+	if err != nil {
+		panic(err)
+	}
+	return golangLRUCache{c}
+}
+
+func (g golangLRUCache) Get(key uint64) (uint64, bool) { return g.c.Get(key) }
+func (g golangLRUCache) Set(key, value uint64)         { g.c.Add(key, value) }
+func (g golangLRUCache) Close()                        {}
+
+type ristrettoCache struct {
+	c *ristretto.Cache[uint64, uint64]
+}
+
+func newRistretto(capacity int) cache {
+	c, err := ristretto.NewCache(&ristretto.Config[uint64, uint64]{
+		NumCounters: int64(capacity) * 10,
+		MaxCost:     int64(capacity),
+		BufferItems: 64,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return ristrettoCache{c}
+}
+
+func (r ristrettoCache) Get(key uint64) (uint64, bool) { return r.c.Get(key) }
+func (r ristrettoCache) Set(key, value uint64)         { r.c.Set(key, value, 1) }
+func (r ristrettoCache) Close()                        { r.c.Close() }
+func (r ristrettoCache) Wait()                         { r.c.Wait() }
+
+type otterCache struct {
+	c *otter.Cache[uint64, uint64]
+}
+
+func newOtter(capacity int) cache {
+	return otterCache{otter.Must(&otter.Options[uint64, uint64]{MaximumSize: capacity})}
+}
+
+func (o otterCache) Get(key uint64) (uint64, bool) { return o.c.GetIfPresent(key) }
+func (o otterCache) Set(key, value uint64)         { o.c.Set(key, value) }
+func (o otterCache) Close()                        { o.c.StopAllGoroutines() }
@@ -0,0 +1,83 @@
+// Command benchmarks runs goutte and other popular Go caches under identical workloads and prints a
+// Markdown comparison of hit ratios and read-through throughput.
+//
+// Run it from this directory with:
+//
+//	go run . -capacity 10000 -keys 100000 -ops 1000000
+//
+// The CI workflow runs it for pull requests, so changes to eviction or locking show their effect
+// against the base branch and the other caches.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+)
+
+func main() {
+	capacity := flag.Int("capacity", 10_000, "maximum number of entries per cache")
+	keys := flag.Int("keys", 100_000, "size of the key space")
+	ops := flag.Int("ops", 1_000_000, "length of each access trace")
+	seed := flag.Int64("seed", 1, "random seed for the access traces")
+	flag.Parse()
+
+	fmt.Printf("capacity=%d keys=%d ops=%d seed=%d\n\n", *capacity, *keys, *ops, *seed)
+	fmt.Println("| workload | cache | hit ratio | ns/op | allocs/op |")
+	fmt.Println("|---|---|---:|---:|---:|")
+	for _, wl := range workloads {
+		trace := wl.trace(rand.New(rand.NewSource(*seed)), *keys, *ops)
+		for _, ct := range contenders {
+			ratio := hitRatio(ct, *capacity, trace)
+			res := testing.Benchmark(func(b *testing.B) { throughput(b, ct, *capacity, trace) })
+			fmt.Printf("| %s | %s | %.2f%% | %d | %d |\n", wl.name, ct.name, ratio*100, res.NsPerOp(), res.AllocsPerOp())
+		}
+	}
+}
+
+// Replays the trace single-threaded as a read-through cache and returns the fraction of hits.
+func hitRatio(ct contender, capacity int, trace []uint64) float64 {
+	c := ct.new(capacity)
+	defer c.Close()
+	wt, async := c.(waiter)
+
+	hits := 0
+	for _, key := range trace {
+		if _, ok := c.Get(key); ok {
+			hits++
+			continue
+		}
+		c.Set(key, key)
+		if async {
+			wt.Wait()
+		}
+	}
+	return float64(hits) / float64(len(trace))
+}
+
+// Measures read-through operations from all available goroutines, each starting at its own offset.
+func throughput(b *testing.B, ct contender, capacity int, trace []uint64) {
+	c := ct.new(capacity)
+	defer c.Close()
+	for _, key := range trace[:min(capacity, len(trace))] {
+		c.Set(key, key)
+	}
+
+	var offset atomic.Uint64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := int(offset.Add(uint64(len(trace)/64)) % uint64(len(trace)))
+		for pb.Next() {
+			key := trace[i]
+			if _, ok := c.Get(key); !ok {
+				c.Set(key, key)
+			}
+			if i++; i == len(trace) {
+				i = 0
+			}
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package main
+
+import "math/rand"
+
+// Named key access trace shared by every contender.
+type workload struct {
+	name  string
+	trace func(r *rand.Rand, keys, ops int) []uint64
+}
+
+var workloads = []workload{
+	{"zipf", zipfTrace},
+	{"uniform", uniformTrace},
+	{"zipf+scan", zipfScanTrace},
+}
+
+// Skewed accesses, typical of hot-key heavy production traffic.
+func zipfTrace(r *rand.Rand, keys, ops int) []uint64 {
+	z := rand.NewZipf(r, 1.01, 1, uint64(keys-1))
+	trace := make([]uint64, ops)
+	for i := range trace {
+		trace[i] = z.Uint64()
+	}
+	return trace
+}
+
+// Uniform accesses, where no eviction policy can do better than capacity/keys.
+func uniformTrace(r *rand.Rand, keys, ops int) []uint64 {
+	trace := make([]uint64, ops)
+	for i := range trace {
+		trace[i] = uint64(r.Intn(keys))
+	}
+	return trace
+}
+
+// Skewed accesses interleaved with long sequential scans over cold keys, which flush pure LRU caches.
+func zipfScanTrace(r *rand.Rand, keys, ops int) []uint64 {
+	trace := zipfTrace(r, keys, ops)
+	scanKey := uint64(keys)
+	for i := 0; i < len(trace); i += 4 {
+		// Every fourth access belongs to a scan that never revisits a key.
+		trace[i] = scanKey
+		scanKey++
+	}
+	return trace
+}
@@ -3,7 +3,9 @@ package goutte
 import (
 	"container/heap"
 	"container/list"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -14,24 +16,104 @@ type entry[K comparable, V any] struct {
 	value      V
 	expiration time.Time
 	exp        *expEntry[K]
+	timer      *wheelTimer[K] // replaces exp when WithTimingWheel is set
+
+	// Set by SoftDelete: the entry is hidden from reads until it expires or is resurrected.
+	tombstone      bool
+	liveExpiration time.Time // expiration to restore on Resurrect
+
+	gen        uint64        // recency generation, see rankTracker
+	inserted   time.Time     // set only when WithMinResidency is enabled
+	streak     *streakState  // set only when WithHitStreakTTL is enabled and the entry has a TTL
+	promo      uint64        // hit count or recency stamp, depending on the promotion strategy
+	refresh    time.Time     // refresh-ahead deadline of a loaded entry; zero if none
+	ttl        time.Duration // TTL of the last write, used by Touch; 0 if unknown or none
+	sliding    bool          // whether hits renew the expiration to ttl from now
+	weight     int64         // weight counted against the maximum weight, see WithWeigher
+	costed     bool          // whether weight was given to SetWithCost rather than computed
+	admitted   bool          // whether the entry is in the admission queue of the policy, see segments
+	referenced bool          // set by accesses and cleared by the clock hand, see PolicyClock
+	slot       int           // index in the slots of PolicyRandom
+	accessed   atomic.Bool   // set by shared reads, see WithSharedReads
 }
 
 // Thread-safe & type-safe LRU cache.
 type Cache[K comparable, V any] struct {
 	capacity int                 // maximum number of items in the cache
-	mu       sync.Mutex          // guards cache and ll below
+	mu       sync.RWMutex        // guards cache and ll below; held for reading by shared reads only
 	ll       *list.List          // doubly-linked list for LRU ordering
 	cache    map[K]*list.Element // map from key to list element
 
 	// Fields for TTL expiration management:
-	expHeap  expHeap[K]    // min-heap of expiration entries
-	updateCh chan struct{} // signals that a new expiration might be sooner
-	done     chan struct{} // closed when the cache is shutting down
+	expHeap  expHeap[K]      // min-heap of expiration entries
+	wheel    *timingWheel[K] // replaces expHeap; nil unless WithTimingWheel is set
+	updateCh chan struct{}   // signals that a new expiration might be sooner
+	done     chan struct{}   // closed when the cache is shutting down
+
+	hits          uint64        // lookups that found a live entry
+	sharedHits    atomic.Uint64 // hits served under the read lock, see WithSharedReads
+	misses        uint64        // lookups that found nothing
+	promotions    uint64        // hits that promoted the entry
+	coalesced     uint64        // misses that waited for another caller's computation
+	expirations   uint64        // entries removed because their TTL elapsed
+	evictions     uint64        // entries evicted to respect the capacity
+	droppedEvents uint64        // events dropped by full subscription queues
+	tombstones    int           // soft-deleted entries still in the list
+	weight        int64         // total weight of the entries, see WithWeigher
+
+	contention  contentionCounters        // lock wait counters, see ContentionStats
+	ranks       *rankTracker              // approximate LRU ranks of hits; nil unless enabled
+	keyStats    *keyStatsTracker[K]       // bounded per-key hit/miss counters; nil unless enabled
+	partitions  *partitionTracker[K]      // per-partition counters; nil unless enabled
+	leases      map[K]*Lease[K, V]        // outstanding leases granted by GetOrLease
+	leaseTTL    time.Duration             // lifetime of leases; 0 for the default
+	flights     map[K]*flight[V]          // computations in progress, see GetOrCompute
+	migration   *migrationState[K, V]     // migration in progress; nil unless Migrate is running
+	missLog     *missLog[K]               // ring buffer of recent misses; nil unless enabled
+	events      *eventLog[K]              // ring buffer of recent mutations; nil unless enabled
+	subscribers []*subscriber[K, V]       // mutation callbacks registered with Subscribe
+	watchers    map[K][]*subscriber[K, V] // per-key channels returned by Watch
+	subQueue    int                       // bound of subscription queues; 0 for the default
+	subOverflow OverflowPolicy            // what full subscription queues drop
+	audit       *auditor                  // background integrity checks; nil unless enabled
+	warmup      *warmupState              // readiness tracking; nil unless enabled
+	labels      callerLabels              // per-caller hit/miss counters, see GetLabeled
+
+	canEvict          func(K, V) bool  // eviction veto hook; nil unless enabled
+	evictAttempts     int              // candidates consulted before forcing an eviction
+	minResidency      time.Duration    // protection window for new entries; 0 unless enabled
+	hitStreak         *HitStreakTTL    // adaptive TTL policy; nil unless enabled
+	promotion         Promotion        // promotion strategy on hits
+	policy            Policy           // eviction policy, see WithPolicy
+	segs              segments         // queues of segmented policies
+	ghosts            *ghostQueue[K]   // keys recently evicted by 2Q; nil unless enabled
+	slots             []*list.Element  // every element, for PolicyRandom to pick from
+	promoStamp        uint64           // recency stamps issued, for PromoteColdHalf
+	refreshAfter      time.Duration    // refresh-ahead delay of loaded entries, see WithRefreshAfter
+	defaultTTL        time.Duration    // TTL applied by Set; 0 for none
+	expireAfterAccess bool             // whether TTLs slide on hits, see WithExpireAfterAccess
+	sharedReads       bool             // whether reads try the read lock first, see WithSharedReads
+	readBuf           *readBuffer      // hits awaiting promotion; nil unless WithReadBuffer is set
+	weigh             func(K, V) int64 // entry weigher; nil unless enabled
+	maxWeight         int64            // maximum total weight; 0 unless a weigher is set
+
+	janitor janitorState // expiration goroutine settings and counters, see WithJanitor
+	clock   Clock        // source of time, see WithClock
+	config  Config       // settings given to NewFromConfig, reported by Stats
+
+	onRemoval func(K, V, EvictionReason) // removal callback; nil unless enabled
+	removed   []removedEntry[K, V]       // removals awaiting delivery once the lock is released
+
+	asyncRemovals removalQueue[K, V] // removals delivered by a worker goroutine, see DeleteMany
 }
 
+// Candidates examined per eviction when only WithMinResidency is set.
+const defaultEvictionScan = 32
+
 // Creates a new LRU cache with a given capacity.
 // K must be a comparable type (like string, int, etc.) and V can be any type.
-func NewCache[K comparable, V any](capacity int) *Cache[K, V] {
+// Optional behavior can be enabled by passing options such as WithRankHistogram.
+func NewCache[K comparable, V any](capacity int, opts ...Option[K, V]) *Cache[K, V] {
 	if capacity <= 0 {
 		panic("capacity must be greater than zero")
 	}
@@ -41,9 +123,23 @@ func NewCache[K comparable, V any](capacity int) *Cache[K, V] {
 		cache:    make(map[K]*list.Element),
 		updateCh: make(chan struct{}, 1),
 		done:     make(chan struct{}),
+		clock:    systemClock{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	// Start the clocks of options that need one, now that it is known.
+	if c.wheel != nil {
+		c.wheel.start = c.clock.Now()
+	}
+	if c.warmup != nil {
+		c.warmup.created = c.clock.Now()
 	}
 	heap.Init(&c.expHeap)
 	go c.expirationProcessor()
+	if c.audit != nil {
+		go c.auditProcessor()
+	}
 	return c
 }
 
@@ -51,86 +147,339 @@ func NewCache[K comparable, V any](capacity int) *Cache[K, V] {
 // If the entry has expired, it is removed and a not-found result is returned.
 // Otherwise, the accessed item is moved to the front of the list (most recently used).
 func (c *Cache[K, V]) Get(key K) (V, bool) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	if c.sharedReads {
+		if val, ok := c.getShared(key, true); ok {
+			return val, true
+		}
+	}
+	c.lock()
+	defer c.unlock()
 
-	if ele, ok := c.cache[key]; ok {
+	return c.getLocked(key)
+}
+
+// Looks up key, recording the hit or miss and promoting the entry on a hit.
+func (c *Cache[K, V]) getLocked(key K) (V, bool) {
+	if ele := c.lookupLocked(key); ele != nil {
+		c.hitLocked(ele)
+		return ele.Value.(*entry[K, V]).value, true
+	}
+
+	c.missLocked(key)
+	var zero V
+	return zero, false
+}
+
+// Retrieves the value associated with the given key like Get, along with its expiration, so callers
+// can derive freshness headers or re-cache the value downstream with a consistent deadline.
+// The expiration is zero if the entry has no TTL; for sliding TTLs, it reflects the renewal by this read.
+func (c *Cache[K, V]) GetWithExpiration(key K) (V, time.Time, bool) {
+	c.lock()
+	defer c.unlock()
+
+	if ele := c.lookupLocked(key); ele != nil {
+		c.hitLocked(ele)
 		ent := ele.Value.(*entry[K, V])
-		if !ent.expiration.IsZero() && time.Now().After(ent.expiration) {
-			c.ll.Remove(ele)
-			delete(c.cache, key)
-			var zero V
-			return zero, false
+		return ent.value, ent.expiration, true
+	}
+
+	c.missLocked(key)
+	var zero V
+	return zero, time.Time{}, false
+}
+
+// Retrieves the value associated with the given key, or def if it is missing or expired.
+func (c *Cache[K, V]) GetOrDefault(key K, def V) V {
+	if val, ok := c.Get(key); ok {
+		return val
+	}
+	return def
+}
+
+// Retrieves the value associated with the given key, or an error wrapping ErrNotFound if it is missing or expired.
+func (c *Cache[K, V]) TryGet(key K) (V, error) {
+	val, ok := c.Get(key)
+	if !ok {
+		return val, fmt.Errorf("%w: %v", ErrNotFound, key)
+	}
+	return val, nil
+}
+
+// Retrieves the value associated with the given key, panicking if it is missing or expired.
+// The panic value is the error TryGet would have returned, so it matches ErrNotFound with errors.Is.
+func (c *Cache[K, V]) MustGet(key K) V {
+	val, err := c.TryGet(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// Retrieves the value associated with the given key without promoting it or recording a hit or miss,
+// so monitoring and debugging code does not distort recency ordering or statistics.
+func (c *Cache[K, V]) Peek(key K) (V, bool) {
+	if c.sharedReads {
+		if val, ok := c.getShared(key, false); ok {
+			return val, true
 		}
-		c.ll.MoveToFront(ele)
-		return ent.value, true
 	}
+	c.lock()
+	defer c.unlock()
 
+	if ele := c.lookupLocked(key); ele != nil {
+		return ele.Value.(*entry[K, V]).value, true
+	}
 	var zero V
 	return zero, false
 }
 
-// Inserts or updates a key-value pair in the cache without a TTL.
+// Reports whether a live entry exists for key, honoring TTLs, without promoting it, recording a hit
+// or miss, or copying the value.
+func (c *Cache[K, V]) Contains(key K) bool {
+	if c.sharedReads {
+		if _, ok := c.getShared(key, false); ok {
+			return true
+		}
+	}
+	c.lock()
+	defer c.unlock()
+
+	return c.lookupLocked(key) != nil
+}
+
+// Returns the list element for key, or nil if it is missing or soft-deleted.
+// An expired entry found on the way is removed.
+func (c *Cache[K, V]) lookupLocked(key K) *list.Element {
+	ele, ok := c.cache[key]
+	if !ok {
+		return nil
+	}
+	ent := ele.Value.(*entry[K, V])
+	if !ent.expiration.IsZero() && c.clock.Now().After(ent.expiration) {
+		c.removeElementLocked(ele, EvictionExpired)
+		return nil
+	}
+	if ent.tombstone {
+		return nil
+	}
+	return ele
+}
+
+// Inserts or updates a key-value pair in the cache, with the default TTL if one is set by WithDefaultTTL.
 func (c *Cache[K, V]) Set(key K, value V) {
-	c.SetWithTTL(key, value, 0)
+	c.lock()
+	defer c.unlock()
+
+	c.writeLocked(key, value, c.defaultTTL)
 }
 
-// Inserts or updates a key-value pair in the cache with an optional TTL.
-// A positive ttl will cause the entry to expire after the given duration.
+// Inserts or updates a key-value pair in the cache with an optional TTL, overriding the default TTL.
+// A positive ttl will cause the entry to expire after the given duration; otherwise it never expires.
 func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.lock()
+	defer c.unlock()
+
+	c.writeLocked(key, value, ttl)
+}
+
+// Inserts a key-value pair only if the key is missing, expired or soft-deleted, with the default TTL
+// if any, and reports whether it did. An existing entry is neither modified nor promoted.
+func (c *Cache[K, V]) SetIfAbsent(key K, value V) bool {
+	c.lock()
+	defer c.unlock()
+
+	if c.lookupLocked(key) != nil {
+		return false
+	}
+	c.writeLocked(key, value, c.defaultTTL)
+	return true
+}
+
+// Updates the value of an existing entry only, keeping its expiration, and reports whether it did.
+// Missing, expired and soft-deleted keys are left absent. The entry is promoted, like Set. A cost
+// given to SetWithCost is kept; use ReplaceWithCost to change it.
+func (c *Cache[K, V]) Replace(key K, value V) bool {
+	c.lock()
+	defer c.unlock()
+
+	return c.replaceLocked(key, value, -1)
+}
+
+// Like Replace, counting cost against the maximum weight; see SetWithCost. A negative cost keeps the
+// cost of the entry, or weighs the new value if it had none.
+func (c *Cache[K, V]) replaceLocked(key K, value V, cost int64) bool {
+	ele := c.lookupLocked(key)
+	if ele == nil {
+		return false
+	}
+	old := ele.Value.(*entry[K, V])
+	if cost < 0 && old.costed {
+		cost = old.weight
+	}
+	ttl, sliding := old.ttl, old.sliding
+	ent := c.setCostLocked(key, value, old.expiration, cost)
+	ent.ttl, ent.sliding = ttl, sliding
+	return true
+}
+
+// Inserts or updates a key-value pair in the cache with a sliding TTL: the entry expires once it has
+// not been read for ttl. A non-positive ttl stores the entry without expiration.
+func (c *Cache[K, V]) SetWithSlidingTTL(key K, value V, ttl time.Duration) {
+	c.lock()
+	defer c.unlock()
+
+	c.writeLocked(key, value, ttl).sliding = ttl > 0
+}
+
+// Inserts or updates a key-value pair expiring after ttl if it is positive, sliding if
+// WithExpireAfterAccess is set. It returns the written entry.
+func (c *Cache[K, V]) writeLocked(key K, value V, ttl time.Duration) *entry[K, V] {
+	return c.writeCostLocked(key, value, ttl, -1)
+}
+
+// Like writeLocked, with an explicit cost to count against the maximum weight; see weighLocked.
+func (c *Cache[K, V]) writeCostLocked(key K, value V, ttl time.Duration, cost int64) *entry[K, V] {
 	var expiration time.Time
 	if ttl > 0 {
-		expiration = time.Now().Add(ttl)
+		expiration = c.clock.Now().Add(ttl)
+	}
+	ent := c.setCostLocked(key, value, expiration, cost)
+	if ttl > 0 {
+		ent.ttl = ttl
+		ent.sliding = c.expireAfterAccess
+	}
+	if c.janitor.degraded {
+		// Help the janitor catch up while it is falling behind.
+		c.janitor.removed += uint64(c.expireDueLocked(c.clock.Now(), c.janitor.SetCleanup))
 	}
+	return ent
+}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// Inserts or updates a key-value pair; a zero expiration means no TTL. It returns the written entry,
+// which may already have been evicted if every other candidate was protected.
+func (c *Cache[K, V]) setLocked(key K, value V, expiration time.Time) *entry[K, V] {
+	return c.setCostLocked(key, value, expiration, -1)
+}
+
+// Like setLocked, with an explicit cost to count against the maximum weight; see weighLocked.
+func (c *Cache[K, V]) setCostLocked(key K, value V, expiration time.Time, cost int64) *entry[K, V] {
+	if c.readBuf != nil {
+		c.drainReadsLocked()
+	}
+	c.revokeLeaseLocked(key)
+	c.invalidateFlightLocked(key)
+	c.logEventLocked(OpSet, key, value, 0)
+	if c.migration != nil {
+		c.mirrorLocked(key)
+	}
 
 	// Update existing key.
 	if ele, ok := c.cache[key]; ok {
 		ent := ele.Value.(*entry[K, V])
-		ent.value = value
-		ent.expiration = expiration
-		c.ll.MoveToFront(ele)
-
-		if ttl > 0 {
-			if ent.exp != nil {
-				// Update existing expiration entry.
-				ent.exp.expiration = expiration
-				heap.Fix(&c.expHeap, ent.exp.index)
-			} else {
-				// Create a new expiration entry and attach it.
-				expE := &expEntry[K]{key: key, expiration: expiration}
-				ent.exp = expE
-				heap.Push(&c.expHeap, expE)
-			}
-			c.signalExpirationUpdate()
+		if !ent.expiration.IsZero() && c.clock.Now().After(ent.expiration) {
+			c.notifyRemovalLocked(ent, EvictionExpired)
 		} else {
-			// TTL is 0: cancel any existing expiration.
-			if ent.exp != nil {
-				ent.exp.canceled = true
-				ent.exp = nil
-			}
+			c.notifyRemovalLocked(ent, EvictionReplaced)
 		}
-		return
+		if ent.tombstone {
+			ent.tombstone = false
+			c.tombstones--
+		}
+		ent.value = value
+		ent.refresh = time.Time{}
+		ent.ttl = 0
+		ent.sliding = false
+		if c.hitStreak != nil {
+			expiration = c.streakWriteLocked(ent, expiration)
+		}
+		c.setExpirationLocked(ent, expiration)
+		c.promoteLocked(ele)
+		c.weighLocked(ent, cost)
+		c.evictLocked()
+		return ent
 	}
 
 	// Add new entry.
-	ent := &entry[K, V]{key: key, value: value, expiration: expiration}
-	ele := c.ll.PushFront(ent)
+	ent := &entry[K, V]{key: key, value: value}
+	if c.minResidency > 0 {
+		ent.inserted = c.clock.Now()
+	}
+	ele := c.insertLocked(ent)
 	c.cache[key] = ele
+	if c.ranks != nil {
+		c.ranks.insert(&ent.gen)
+	}
+	c.stampLocked(ent)
 
 	// If the item has a TTL, attach an expiration entry.
-	if ttl > 0 {
-		expE := &expEntry[K]{key: key, expiration: expiration}
-		ent.exp = expE
-		heap.Push(&c.expHeap, expE)
-		c.signalExpirationUpdate()
+	if c.hitStreak != nil {
+		expiration = c.streakWriteLocked(ent, expiration)
+	}
+	c.setExpirationLocked(ent, expiration)
+	c.weighLocked(ent, cost)
+
+	// Evict the least recently used items if over capacity.
+	c.evictLocked()
+	return ent
+}
+
+// Replaces the value of the entry of ele in place, keeping its expiration and TTL settings, as field
+// and counter updates do. Like setCostLocked, it revokes the lease and invalidates any load in progress
+// for the key, reports the previous value as replaced, and re-weighs the entry unless its cost was
+// given to SetWithCost.
+func (c *Cache[K, V]) updateLocked(ele *list.Element, value V) {
+	ent := ele.Value.(*entry[K, V])
+	c.revokeLeaseLocked(ent.key)
+	c.invalidateFlightLocked(ent.key)
+	c.logEventLocked(OpSet, ent.key, value, 0)
+	if c.migration != nil {
+		c.mirrorLocked(ent.key)
 	}
+	c.notifyRemovalLocked(ent, EvictionReplaced)
+	ent.value = value
+	if !ent.costed {
+		c.weighLocked(ent, -1)
+		c.evictLocked()
+	}
+}
 
-	// Evict the least recently used item if over capacity.
-	if c.ll.Len() > c.capacity {
-		c.removeOldestLocked()
+// Replaces the expiration of an existing entry, keeping the expiration heap in sync.
+// A zero expiration cancels any pending expiration.
+func (c *Cache[K, V]) setExpirationLocked(ent *entry[K, V], expiration time.Time) {
+	ent.expiration = expiration
+	if c.wheel != nil {
+		if expiration.IsZero() {
+			if ent.timer != nil {
+				c.wheel.cancel(ent.timer)
+				ent.timer = nil
+			}
+			return
+		}
+		var sooner bool
+		ent.timer, sooner = c.wheel.schedule(ent.timer, ent.key, expiration)
+		if sooner {
+			c.signalExpirationUpdate()
+		}
+		return
+	}
+	if !expiration.IsZero() {
+		if ent.exp != nil {
+			// Update existing expiration entry.
+			ent.exp.expiration = expiration
+			heap.Fix(&c.expHeap, ent.exp.index)
+		} else {
+			// Create a new expiration entry and attach it.
+			expE := &expEntry[K]{key: ent.key, expiration: expiration}
+			ent.exp = expE
+			heap.Push(&c.expHeap, expE)
+		}
+		c.signalExpirationUpdate()
+	} else {
+		// No expiration: cancel any existing one.
+		if ent.exp != nil {
+			ent.exp.canceled = true
+			ent.exp = nil
+		}
 	}
 }
 
@@ -143,26 +492,163 @@ func (c *Cache[K, V]) signalExpirationUpdate() {
 }
 
 func (c *Cache[K, V]) removeOldestLocked() {
-	ele := c.ll.Back()
+	ele := c.victimLocked()
 	if ele == nil {
 		return
 	}
+	reason := EvictionCapacity
 	ent := ele.Value.(*entry[K, V])
+	if !ent.expiration.IsZero() && c.clock.Now().After(ent.expiration) {
+		reason = EvictionExpired
+	} else if c.partitions != nil && !ent.tombstone {
+		c.partitions.stats(ent.key).Evictions++
+	}
+	c.removeElementLocked(ele, reason)
+}
+
+// Selects the element to evict for capacity: the least recently used one, or the one chosen by the
+// eviction policy, unless it is protected by WithMinResidency or vetoed by WithEvictionVeto, in which
+// case the next candidates are examined. After a bounded number of skipped candidates, the first
+// candidate is evicted regardless.
+func (c *Cache[K, V]) victimLocked() *list.Element {
+	if c.readBuf != nil {
+		c.drainReadsLocked()
+	} else if c.sharedReads {
+		c.applySharedHitsLocked()
+	}
+	back := c.candidateLocked()
+	if c.canEvict == nil && c.minResidency == 0 {
+		return back
+	}
+	limit := c.evictAttempts
+	if limit == 0 {
+		limit = defaultEvictionScan
+	}
+	now := c.clock.Now()
+	ele := back
+	for n := 0; n < limit && ele != nil; n, ele = n+1, c.nextCandidateLocked(ele) {
+		ent := ele.Value.(*entry[K, V])
+		if ent.tombstone || (!ent.expiration.IsZero() && now.After(ent.expiration)) {
+			return ele
+		}
+		if c.minResidency > 0 && now.Sub(ent.inserted) < c.minResidency {
+			continue
+		}
+		if c.canEvict != nil && !c.canEvict(ent.key, ent.value) {
+			continue
+		}
+		return ele
+	}
+	return back
+}
+
+// Unlinks an element from the list and the map, canceling its pending expiration.
+// The removal is reported to the OnRemoval callback with the given reason.
+func (c *Cache[K, V]) removeElementLocked(ele *list.Element, reason EvictionReason) {
+	ent := ele.Value.(*entry[K, V])
+	c.notifyRemovalLocked(ent, reason)
+	if ent.tombstone {
+		c.tombstones--
+	}
+	c.weight -= ent.weight
 	if ent.exp != nil {
 		ent.exp.canceled = true
 	}
+	if ent.timer != nil {
+		c.wheel.cancel(ent.timer)
+	}
+	if c.ranks != nil {
+		c.ranks.remove(ent.gen)
+	}
+	c.unlinkLocked(ele, reason)
 	c.ll.Remove(ele)
 	delete(c.cache, ent.key)
 }
 
+// Moves an element to the front of the list (most recently used), unless the eviction policy keeps it
+// in place, and reports whether it moved.
+func (c *Cache[K, V]) promoteLocked(ele *list.Element) bool {
+	ent := ele.Value.(*entry[K, V])
+	switch c.policy.kind {
+	case policyClock:
+		ent.referenced = true
+		return false
+	case policyFIFO, policyRandom:
+		return false
+	}
+	if ent.admitted && !c.accessAdmittedLocked(ele) {
+		return false
+	}
+	if c.ranks != nil {
+		c.ranks.promote(&ent.gen)
+	}
+	c.stampLocked(ent)
+	c.ll.MoveToFront(ele)
+	if c.policy.kind == policySLRU {
+		c.demoteLocked()
+	}
+	return true
+}
+
+// Records a read hit on an element and promotes it, subject to the promotion strategy.
+func (c *Cache[K, V]) hitLocked(ele *list.Element) {
+	c.hits++
+	if ent := ele.Value.(*entry[K, V]); ent.sliding {
+		if renewed := c.clock.Now().Add(ent.ttl); renewed.After(ent.expiration) {
+			c.setExpirationLocked(ent, renewed)
+			if c.migration != nil {
+				c.mirrorLocked(ent.key)
+			}
+		}
+	}
+	if c.passthroughLocked() {
+		return
+	}
+	ent := ele.Value.(*entry[K, V])
+	if ent.streak != nil {
+		c.streakHitLocked(ent)
+	}
+	if c.ranks != nil {
+		c.ranks.recordHit(ent.gen)
+	}
+	if c.keyStats != nil {
+		c.keyStats.record(ent.key, true)
+	}
+	if c.partitions != nil {
+		c.partitions.stats(ent.key).Hits++
+	}
+	if c.shouldPromoteLocked(ent) && c.promoteLocked(ele) {
+		c.promotions++
+	}
+}
+
+// Records a read miss on key.
+func (c *Cache[K, V]) missLocked(key K) {
+	c.misses++
+	if c.passthroughLocked() {
+		return
+	}
+	if c.keyStats != nil {
+		c.keyStats.record(key, false)
+	}
+	if c.partitions != nil {
+		c.partitions.stats(key).Misses++
+	}
+	if c.missLog != nil {
+		c.missLog.record(key, c.clock.Now())
+	}
+}
+
 func (c *Cache[K, V]) expirationProcessor() {
-	var timer *time.Timer
+	var timer Timer
 
 	for {
-		c.mu.Lock()
+		c.lock()
 		var waitDuration time.Duration
-		now := time.Now()
-		if c.expHeap.Len() == 0 {
+		now := c.clock.Now()
+		if c.wheel != nil {
+			waitDuration = c.wheelWaitLocked(now)
+		} else if c.expHeap.Len() == 0 {
 			// No items with TTL. Wait for a long time (or until an update).
 			waitDuration = time.Hour
 		} else {
@@ -171,7 +657,7 @@ func (c *Cache[K, V]) expirationProcessor() {
 			// If the entry is canceled, remove it immediately.
 			if next.canceled {
 				heap.Pop(&c.expHeap)
-				c.mu.Unlock()
+				c.unlock()
 				continue
 			}
 			if now.Before(next.expiration) {
@@ -181,16 +667,16 @@ func (c *Cache[K, V]) expirationProcessor() {
 				waitDuration = 0
 			}
 		}
-		c.mu.Unlock()
+		c.unlock()
 
 		// Create or reset the timer.
 		if timer == nil {
-			timer = time.NewTimer(waitDuration)
+			timer = c.clock.NewTimer(waitDuration)
 		} else {
 			if !timer.Stop() {
 				// Drain the channel if needed.
 				select {
-				case <-timer.C:
+				case <-timer.C():
 				default:
 				}
 			}
@@ -199,7 +685,7 @@ func (c *Cache[K, V]) expirationProcessor() {
 
 		// Wait for the timer to fire, an update, or shutdown.
 		select {
-		case <-timer.C:
+		case <-timer.C():
 			// Time to remove expired items.
 		case <-c.updateCh:
 			// An update was signaled; loop around to recalc waitDuration.
@@ -209,60 +695,262 @@ func (c *Cache[K, V]) expirationProcessor() {
 			return
 		}
 
-		// Remove all expired entries.
-		c.mu.Lock()
-		now = time.Now()
-		for c.expHeap.Len() > 0 {
-			next := c.expHeap[0]
-			// Skip canceled entries.
-			if next.canceled {
-				heap.Pop(&c.expHeap)
-				continue
-			}
-			if now.Before(next.expiration) {
-				break
-			}
-			// Pop from the heap.
+		// Remove expired entries, at most one batch per locked pass.
+		c.lock()
+		c.janitor.removed += uint64(c.expireDueLocked(c.clock.Now(), c.janitor.BatchSize))
+		c.janitor.passes++
+		notify, stats := c.updateJanitorHealthLocked()
+		c.unlock()
+
+		if notify {
+			c.janitor.OnHealthChange(stats)
+		}
+	}
+}
+
+// Removes up to limit entries whose expiration has passed (all of them if limit is 0),
+// discarding canceled heap entries on the way. Entries sharing the deadline of the last entry removed,
+// such as a group set by SetGroupDeadline, are removed past the limit, so a group is never split
+// across passes. It returns the number of entries removed.
+func (c *Cache[K, V]) expireDueLocked(now time.Time, limit int) int {
+	if c.wheel != nil {
+		return c.expireWheelLocked(now, limit)
+	}
+	removed := 0
+	var last time.Time // deadline of the last entry removed
+	for c.expHeap.Len() > 0 {
+		next := c.expHeap[0]
+		// Skip canceled entries.
+		if next.canceled {
 			heap.Pop(&c.expHeap)
-			// Remove from cache if it still exists and its expiration matches.
-			if ele, ok := c.cache[next.key]; ok {
-				ent := ele.Value.(*entry[K, V])
-				// Only remove if the stored expiration is expired.
-				if !ent.expiration.IsZero() && !now.Before(ent.expiration) {
-					c.ll.Remove(ele)
-					delete(c.cache, next.key)
-				}
+			continue
+		}
+		if now.Before(next.expiration) {
+			break
+		}
+		if limit > 0 && removed >= limit && !next.expiration.Equal(last) {
+			break
+		}
+		// Pop from the heap.
+		heap.Pop(&c.expHeap)
+		// Remove from cache if it still exists and its expiration matches.
+		if ele, ok := c.cache[next.key]; ok {
+			ent := ele.Value.(*entry[K, V])
+			// Only remove if the stored expiration is expired.
+			if !ent.expiration.IsZero() && !now.Before(ent.expiration) {
+				last = ent.expiration
+				c.removeElementLocked(ele, EvictionExpired)
+				removed++
 			}
 		}
-		c.mu.Unlock()
 	}
+	return removed
 }
 
 // Removes a key from the cache if it exists.
 func (c *Cache[K, V]) Delete(key K) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.lock()
+	defer c.unlock()
 
-	if ele, ok := c.cache[key]; ok {
+	c.deleteLocked(key)
+}
+
+// Atomically removes an entry and returns its value, e.g. to consume a one-shot token exactly once.
+// The lookup counts as a hit or miss like Get.
+func (c *Cache[K, V]) GetAndDelete(key K) (V, bool) {
+	c.lock()
+	defer c.unlock()
+
+	val, ok := c.getLocked(key)
+	if ok {
+		c.deleteLocked(key)
+	}
+	return val, ok
+}
+
+// Removes the least recently used live entry and returns it, e.g. to shed load under memory pressure
+// or drain the cache in LRU order. Its removal is reported with EvictionDeleted. The third result is
+// false if the cache holds no live entry.
+func (c *Cache[K, V]) RemoveOldest() (K, V, bool) {
+	c.lock()
+	defer c.unlock()
+
+	return c.removeEndLocked(false)
+}
+
+// Removes the most recently used live entry and returns it, e.g. to roll back a speculative insert.
+// Its removal is reported with EvictionDeleted. The third result is false if the cache holds no live entry.
+func (c *Cache[K, V]) RemoveNewest() (K, V, bool) {
+	c.lock()
+	defer c.unlock()
+
+	return c.removeEndLocked(true)
+}
+
+// Removes and returns the live entry at the given end of the list.
+func (c *Cache[K, V]) removeEndLocked(front bool) (K, V, bool) {
+	ele := c.liveEndLocked(front)
+	if ele == nil {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+	ent := ele.Value.(*entry[K, V])
+	c.deleteLocked(ent.key)
+	return ent.key, ent.value, true
+}
+
+// Returns the first live element from the front (most recently used) or back of the list, removing
+// expired entries on the way and skipping soft-deleted ones; nil if there is none.
+func (c *Cache[K, V]) liveEndLocked(front bool) *list.Element {
+	ele := c.ll.Back()
+	if front {
+		ele = c.ll.Front()
+	}
+	now := c.clock.Now()
+	for ele != nil {
+		next := ele.Prev()
+		if front {
+			next = ele.Next()
+		}
 		ent := ele.Value.(*entry[K, V])
-		if ent.exp != nil {
-			ent.exp.canceled = true
+		switch {
+		case !ent.expiration.IsZero() && now.After(ent.expiration):
+			c.removeElementLocked(ele, EvictionExpired)
+		case !ent.tombstone:
+			return ele
+		}
+		ele = next
+	}
+	return nil
+}
+
+// Retrieves several keys under a single lock acquisition, recording hits and misses like Get.
+// It returns the values found and the keys that are missing, expired or soft-deleted, in the order given.
+func (c *Cache[K, V]) GetMany(keys []K) (map[K]V, []K) {
+	c.lock()
+	defer c.unlock()
+
+	found := make(map[K]V, len(keys))
+	var missing []K
+	for _, key := range keys {
+		if value, ok := c.getLocked(key); ok {
+			found[key] = value
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	return found, missing
+}
+
+// Inserts or updates several key-value pairs under a single lock acquisition, with the default TTL
+// if one is set. Map iteration order is unspecified, so it is also the order in which the new entries
+// are promoted; when items exceed the capacity, which of them are retained is unspecified.
+func (c *Cache[K, V]) SetMany(items map[K]V) {
+	c.lock()
+	defer c.unlock()
+
+	for key, value := range items {
+		c.writeLocked(key, value, c.defaultTTL)
+	}
+}
+
+// Removes several keys under a single lock acquisition.
+// It returns how many live entries were removed; missing, expired and soft-deleted keys are not counted.
+// Unlike other operations, DeleteMany does not run the OnRemoval callback on the caller's goroutine:
+// the removals are queued, in order, for a worker goroutine, so invalidating many keys does not wait
+// for the callback. The queue is bounded; once it is full, DeleteMany blocks until the worker catches
+// up. The callback may thus still be running for these keys after DeleteMany returns, and its calls
+// may interleave with those of other operations.
+func (c *Cache[K, V]) DeleteMany(keys []K) int {
+	c.lock()
+	defer c.unlockAsync()
+
+	removed := 0
+	for _, key := range keys {
+		if c.deleteLocked(key) {
+			removed++
 		}
-		c.ll.Remove(ele)
-		delete(c.cache, key)
 	}
+	return removed
+}
+
+// Removes a key along with its tombstone and lease, reporting whether a live entry was removed.
+func (c *Cache[K, V]) deleteLocked(key K) bool {
+	c.revokeLeaseLocked(key)
+	c.invalidateFlightLocked(key)
+	if c.migration != nil {
+		c.mirrorLocked(key)
+	}
+	if ele := c.lookupLocked(key); ele != nil {
+		c.removeElementLocked(ele, EvictionDeleted)
+		return true
+	}
+	if ele, ok := c.cache[key]; ok {
+		// Drop the tombstone too.
+		c.removeElementLocked(ele, EvictionDeleted)
+	}
+	return false
 }
 
 // Clears all entries from the cache.
 func (c *Cache[K, V]) Dump() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.lock()
+	defer c.unlock()
+
+	c.dumpLocked()
+}
 
+func (c *Cache[K, V]) dumpLocked() {
+	if c.migration != nil {
+		c.mirrorDumpLocked()
+	}
+	if c.onRemoval != nil || c.events != nil || len(c.subscribers) > 0 || len(c.watchers) > 0 {
+		for ele := c.ll.Back(); ele != nil; ele = ele.Prev() {
+			c.notifyRemovalLocked(ele.Value.(*entry[K, V]), EvictionCleared)
+		}
+	}
 	c.ll.Init()
+	c.resetPolicyLocked()
 	c.cache = make(map[K]*list.Element)
+	c.tombstones = 0
+	c.weight = 0
 	// Reset the expiration heap.
 	c.expHeap = nil
 	heap.Init(&c.expHeap)
+	if c.wheel != nil {
+		c.wheel.reset()
+	}
+	if c.ranks != nil {
+		c.ranks.reset()
+	}
+	for key := range c.leases {
+		c.revokeLeaseLocked(key)
+	}
+	for key := range c.flights {
+		c.invalidateFlightLocked(key)
+	}
+	if c.audit != nil {
+		c.audit.listPos = nil
+	}
+}
+
+// Returns the number of live entries: soft-deleted entries and expired entries still awaiting the
+// expiration goroutine are not counted. Len does not remove expired entries; see DeleteExpired.
+func (c *Cache[K, V]) Len() int {
+	c.lock()
+	defer c.unlock()
+
+	due, _ := c.countDueLocked(c.clock.Now())
+	return c.ll.Len() - c.tombstones - due
+}
+
+// Returns the maximum number of entries the cache holds, as set by NewCache or SetCapacity.
+func (c *Cache[K, V]) Capacity() int {
+	c.lock()
+	defer c.unlock()
+
+	return c.capacity
 }
 
 // Dynamically adjusts the capacity of the cache.
@@ -273,14 +961,19 @@ func (c *Cache[K, V]) SetCapacity(newCapacity int) {
 		panic("new capacity must be greater than zero")
 	}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.lock()
+	defer c.unlock()
 
+	c.setCapacityLocked(newCapacity)
+}
+
+func (c *Cache[K, V]) setCapacityLocked(newCapacity int) {
 	c.capacity = newCapacity
-	// Evict least recently used items until the cache fits the new capacity.
-	for c.ll.Len() > c.capacity {
-		c.removeOldestLocked()
+	if c.ranks != nil {
+		c.ranks.resize(newCapacity)
 	}
+	// Evict least recently used items until the cache fits the new capacity.
+	c.evictLocked()
 }
 
 // Stops the background expiration goroutine.
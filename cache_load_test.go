@@ -124,5 +124,9 @@ func TestCacheLoad(t *testing.T) {
 		}(i)
 	}
 	wg.Wait()
+
+	if err := c.Verify(); err != nil {
+		t.Error(err)
+	}
 	c.Close()
 }
@@ -1,7 +1,10 @@
 package goutte_test
 
 import (
+	"errors"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -18,6 +21,101 @@ func TestCacheBasic(t *testing.T) {
 	}
 }
 
+func TestCacheGetVariants(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+	cache.Set("a", 1)
+
+	if val := cache.GetOrDefault("a", 7); val != 1 {
+		t.Errorf("Expected GetOrDefault to return 1 for key 'a', got %v", val)
+	}
+	if val := cache.GetOrDefault("b", 7); val != 7 {
+		t.Errorf("Expected GetOrDefault to return the default 7 for key 'b', got %v", val)
+	}
+
+	if val, err := cache.TryGet("a"); err != nil || val != 1 {
+		t.Errorf("Expected TryGet to return 1 for key 'a', got %v (err: %v)", val, err)
+	}
+	if _, err := cache.TryGet("b"); !errors.Is(err, goutte.ErrNotFound) {
+		t.Errorf("Expected TryGet to return ErrNotFound for key 'b', got %v", err)
+	}
+
+	if val := cache.MustGet("a"); val != 1 {
+		t.Errorf("Expected MustGet to return 1 for key 'a', got %v", val)
+	}
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, goutte.ErrNotFound) {
+			t.Errorf("Expected MustGet to panic with ErrNotFound, got %v", err)
+		}
+	}()
+	cache.MustGet("b")
+}
+
+func TestCacheGetWithExpiration(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+
+	before := time.Now()
+	cache.SetWithTTL("a", 1, time.Minute)
+	cache.Set("b", 2)
+
+	val, exp, ok := cache.GetWithExpiration("a")
+	if !ok || val != 1 || exp.Before(before.Add(time.Minute)) || exp.After(time.Now().Add(time.Minute)) {
+		t.Errorf("Expected 'a' to expire in a minute, got %v at %v (found: %v)", val, exp, ok)
+	}
+	if val, exp, ok := cache.GetWithExpiration("b"); !ok || val != 2 || !exp.IsZero() {
+		t.Errorf("Expected 'b' without expiration, got %v at %v (found: %v)", val, exp, ok)
+	}
+	if _, _, ok := cache.GetWithExpiration("missing"); ok {
+		t.Error("Expected a missing key not to be found")
+	}
+}
+
+func TestCachePeek(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+
+	if val, ok := cache.Peek("a"); !ok || val != 1 {
+		t.Errorf("Expected Peek of 'a' to return 1, got %v (found: %v)", val, ok)
+	}
+	if _, ok := cache.Peek("missing"); ok {
+		t.Error("Expected Peek of a missing key to report not found")
+	}
+
+	// Peek does not promote 'a', so it is still the eviction candidate.
+	cache.Set("c", 3)
+	if _, ok := cache.Peek("a"); ok {
+		t.Error("Expected 'a' to be evicted despite being peeked")
+	}
+}
+
+func TestCacheContains(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.SetWithTTL("b", 2, 20*time.Millisecond)
+
+	if !cache.Contains("a") || !cache.Contains("b") {
+		t.Error("Expected 'a' and 'b' to be present")
+	}
+	if cache.Contains("missing") {
+		t.Error("Expected a missing key not to be present")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if cache.Contains("b") {
+		t.Error("Expected expired 'b' not to be present")
+	}
+
+	// Contains does not promote 'a'.
+	cache.Set("c", 3)
+	cache.Set("d", 4)
+	if cache.Contains("a") {
+		t.Error("Expected 'a' to be evicted despite the existence checks")
+	}
+}
+
 func TestCacheEviction(t *testing.T) {
 	cache := goutte.NewCache[string, int](2)
 	defer cache.Close()
@@ -55,6 +153,50 @@ func TestCacheUpdate(t *testing.T) {
 	}
 }
 
+func TestCacheSetIfAbsent(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+
+	if !cache.SetIfAbsent("a", 1) {
+		t.Error("Expected the first SetIfAbsent to insert")
+	}
+	if cache.SetIfAbsent("a", 2) {
+		t.Error("Expected the second SetIfAbsent not to insert")
+	}
+	if val, _ := cache.Get("a"); val != 1 {
+		t.Errorf("Expected the first writer to win, got %d", val)
+	}
+
+	cache.SetWithTTL("b", 1, 20*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	if !cache.SetIfAbsent("b", 2) {
+		t.Error("Expected SetIfAbsent to replace an expired entry")
+	}
+}
+
+func TestCacheReplace(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+
+	if cache.Replace("a", 1) || cache.Contains("a") {
+		t.Error("Expected Replace not to insert a missing key")
+	}
+
+	cache.SetWithTTL("a", 1, 30*time.Millisecond)
+	if !cache.Replace("a", 2) {
+		t.Error("Expected Replace to update an existing key")
+	}
+	if val, _ := cache.Get("a"); val != 2 {
+		t.Errorf("Expected 'a' to be 2, got %d", val)
+	}
+
+	// The expiration is kept.
+	time.Sleep(50 * time.Millisecond)
+	if cache.Replace("a", 3) || cache.Contains("a") {
+		t.Error("Expected 'a' to have expired with its original TTL")
+	}
+}
+
 func TestCacheDelete(t *testing.T) {
 	cache := goutte.NewCache[string, int](2)
 	defer cache.Close()
@@ -66,6 +208,179 @@ func TestCacheDelete(t *testing.T) {
 	}
 }
 
+func TestCacheGetAndDelete(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+	cache.Set("token", 1)
+
+	var wg sync.WaitGroup
+	var consumed atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if val, ok := cache.GetAndDelete("token"); ok && val == 1 {
+				consumed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := consumed.Load(); n != 1 {
+		t.Errorf("Expected the token to be consumed exactly once, got %d", n)
+	}
+	if cache.Contains("token") {
+		t.Error("Expected the token to be deleted")
+	}
+}
+
+func TestCacheRemoveOldest(t *testing.T) {
+	cache := goutte.NewCache[string, int](4)
+	defer cache.Close()
+	cache.SetWithTTL("expired", 0, time.Millisecond)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Get("a")
+	time.Sleep(10 * time.Millisecond)
+
+	// Expired entries are skipped.
+	var drained []string
+	for {
+		k, v, ok := cache.RemoveOldest()
+		if !ok {
+			break
+		}
+		if v != map[string]int{"a": 1, "b": 2}[k] {
+			t.Errorf("Unexpected value %d for key %q", v, k)
+		}
+		drained = append(drained, k)
+	}
+	if len(drained) != 2 || drained[0] != "b" || drained[1] != "a" {
+		t.Errorf("Expected to drain [b a] in LRU order, got %v", drained)
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Expected an empty cache, got Len %d", cache.Len())
+	}
+}
+
+func TestCacheRemoveNewest(t *testing.T) {
+	cache := goutte.NewCache[string, int](4)
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	cache.SoftDelete("c", time.Minute)
+
+	// Soft-deleted entries are skipped.
+	if k, v, ok := cache.RemoveNewest(); !ok || k != "b" || v != 2 {
+		t.Errorf("Expected to remove 'b', got %q = %d (found: %v)", k, v, ok)
+	}
+	if k, _, ok := cache.RemoveNewest(); !ok || k != "a" {
+		t.Errorf("Expected to remove 'a', got %q (found: %v)", k, ok)
+	}
+	if _, _, ok := cache.RemoveNewest(); ok {
+		t.Error("Expected no live entry to be left")
+	}
+}
+
+func TestCacheGetManySetMany(t *testing.T) {
+	cache := goutte.NewCache[string, int](4)
+	defer cache.Close()
+	cache.SetMany(map[string]int{"a": 1, "b": 2, "c": 3})
+	cache.SetWithTTL("d", 4, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	found, missing := cache.GetMany([]string{"a", "missing", "c", "d"})
+	if len(found) != 2 || found["a"] != 1 || found["c"] != 3 {
+		t.Errorf("Expected a=1 and c=3 to be found, got %v", found)
+	}
+	if !slices.Equal(missing, []string{"missing", "d"}) {
+		t.Errorf("Expected [missing d] to be missing, got %v", missing)
+	}
+	if val, ok := cache.Get("b"); !ok || val != 2 {
+		t.Errorf("Expected key 'b' to have value 2, got %v (found: %v)", val, ok)
+	}
+}
+
+func TestCacheDeleteMany(t *testing.T) {
+	cache := goutte.NewCache[string, int](4)
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+
+	if n := cache.DeleteMany([]string{"a", "c", "missing"}); n != 2 {
+		t.Errorf("Expected DeleteMany to remove 2 keys, got %d", n)
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected key 'a' to be deleted")
+	}
+	if _, ok := cache.Get("c"); ok {
+		t.Error("Expected key 'c' to be deleted")
+	}
+	if val, ok := cache.Get("b"); !ok || val != 2 {
+		t.Errorf("Expected key 'b' to have value 2, got %v (found: %v)", val, ok)
+	}
+}
+
+func TestCacheDeleteManyAsyncRemoval(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var removed []int
+	cache := goutte.NewCache(5000, goutte.WithOnRemoval(func(key, _ int, reason goutte.EvictionReason) {
+		<-release
+		mu.Lock()
+		defer mu.Unlock()
+		if reason == goutte.EvictionDeleted {
+			removed = append(removed, key)
+		}
+	}))
+	defer cache.Close()
+	keys := make([]int, 3000)
+	for i := range keys {
+		keys[i] = i
+		cache.Set(i, i)
+	}
+
+	// DeleteMany does not wait for the blocked callback.
+	returned := make(chan int)
+	go func() { returned <- cache.DeleteMany(keys[:10]) }()
+	select {
+	case n := <-returned:
+		if n != 10 {
+			t.Errorf("Expected DeleteMany to remove 10 keys, got %d", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected DeleteMany to return while the callback is blocked")
+	}
+
+	// Past the bound of the queue, it waits for the callback to catch up.
+	go func() { returned <- cache.DeleteMany(keys[10:]) }()
+	select {
+	case <-returned:
+		t.Fatal("Expected DeleteMany to block once the removal queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	if n := <-returned; n != 2990 {
+		t.Errorf("Expected DeleteMany to remove 2990 keys, got %d", n)
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+		mu.Lock()
+		done := len(removed) == len(keys)
+		mu.Unlock()
+		if done || time.Now().After(deadline) {
+			break
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Equal(removed, keys) {
+		t.Errorf("Expected the removals of the 3000 keys in order, got %d removals", len(removed))
+	}
+}
+
 func TestCacheConcurrency(t *testing.T) {
 	cache := goutte.NewCache[int, int](1000)
 	defer cache.Close()
@@ -194,6 +509,39 @@ func TestCacheSetCapacity(t *testing.T) {
 	}
 }
 
+func TestCacheLenCapacity(t *testing.T) {
+	cache := goutte.NewCache[string, int](3)
+	defer cache.Close()
+
+	if cache.Len() != 0 || cache.Capacity() != 3 {
+		t.Errorf("Expected empty cache of capacity 3, got Len %d, Capacity %d", cache.Len(), cache.Capacity())
+	}
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.SetWithTTL("c", 3, 20*time.Millisecond)
+	cache.Set("d", 4)
+	if cache.Len() != 3 {
+		t.Errorf("Expected Len 3 at capacity, got %d", cache.Len())
+	}
+
+	// Expired and soft-deleted entries are not counted.
+	cache.SoftDelete("b", time.Minute)
+	time.Sleep(50 * time.Millisecond)
+	if cache.Len() != 1 {
+		t.Errorf("Expected Len 1, got %d", cache.Len())
+	}
+	cache.Resurrect("b")
+	if cache.Len() != 2 {
+		t.Errorf("Expected Len 2 after Resurrect, got %d", cache.Len())
+	}
+
+	cache.SetCapacity(1)
+	if cache.Len() != 1 || cache.Capacity() != 1 {
+		t.Errorf("Expected Len 1 and Capacity 1, got Len %d, Capacity %d", cache.Len(), cache.Capacity())
+	}
+}
+
 func TestCacheTTLUpdate(t *testing.T) {
 	cache := goutte.NewCache[string, int](2)
 	defer cache.Close()
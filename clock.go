@@ -0,0 +1,139 @@
+package goutte
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Source of time for a cache: expirations, sliding TTLs, residency windows, event and miss timestamps
+// and the expiration goroutine all use it; see WithClock.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer created by a Clock, behaving like *time.Timer.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Replaces the system clock of the cache, e.g. with a ManualClock to test TTL behavior without
+// sleeping. Background tasks running on a fixed period, such as the auditor and migrations, keep
+// using the system clock.
+func WithClock[K comparable, V any](clock Clock) Option[K, V] {
+	if clock == nil {
+		panic("clock must not be nil")
+	}
+	return func(c *Cache[K, V]) {
+		c.clock = clock
+	}
+}
+
+// Clock backed by the time package.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTimer(d time.Duration) Timer { return systemTimer{time.NewTimer(d)} }
+
+type systemTimer struct{ *time.Timer }
+
+func (t systemTimer) C() <-chan time.Time { return t.Timer.C }
+
+// Clock that only moves when told to, for deterministic tests. Timers fire, in deadline order, when
+// Advance or Set moves the clock past their deadline. It is safe for concurrent use.
+type ManualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*manualTimer // active timers
+}
+
+// Creates a manual clock reading now.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+func (m *ManualClock) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.now
+}
+
+func (m *ManualClock) NewTimer(d time.Duration) Timer {
+	t := &manualTimer{clock: m, c: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+// Moves the clock forward by d and fires the timers that are due.
+func (m *ManualClock) Advance(d time.Duration) {
+	m.mu.Lock()
+	m.setLocked(m.now.Add(d))
+	m.mu.Unlock()
+}
+
+// Moves the clock to now and fires the timers that are due. The clock may move backwards.
+func (m *ManualClock) Set(now time.Time) {
+	m.mu.Lock()
+	m.setLocked(now)
+	m.mu.Unlock()
+}
+
+func (m *ManualClock) setLocked(now time.Time) {
+	m.now = now
+	slices.SortStableFunc(m.timers, func(a, b *manualTimer) int {
+		return a.when.Compare(b.when)
+	})
+	fired := 0
+	for _, t := range m.timers {
+		if t.when.After(now) {
+			break
+		}
+		select {
+		case t.c <- now:
+		default:
+		}
+		fired++
+	}
+	clear(m.timers[:fired])
+	m.timers = slices.Delete(m.timers, 0, fired)
+}
+
+// Timer of a ManualClock.
+type manualTimer struct {
+	clock *ManualClock
+	c     chan time.Time
+	when  time.Time
+}
+
+func (t *manualTimer) C() <-chan time.Time { return t.c }
+
+func (t *manualTimer) Stop() bool {
+	m := t.clock
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := slices.Index(m.timers, t)
+	if i < 0 {
+		return false
+	}
+	m.timers = slices.Delete(m.timers, i, i+1)
+	return true
+}
+
+func (t *manualTimer) Reset(d time.Duration) bool {
+	active := t.Stop()
+	m := t.clock
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t.when = m.now.Add(d)
+	m.timers = append(m.timers, t)
+	// Fire right away if the deadline has already passed, as time.Timer does.
+	m.setLocked(m.now)
+	return active
+}
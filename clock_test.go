@@ -0,0 +1,86 @@
+package goutte_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheWithClock(t *testing.T) {
+	clock := goutte.NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	expired := make(chan string, 4)
+	cache := goutte.NewCache(4,
+		goutte.WithClock[string, int](clock),
+		goutte.WithOnRemoval(func(key string, _ int, reason goutte.EvictionReason) {
+			if reason == goutte.EvictionExpired {
+				expired <- key
+			}
+		}))
+	defer cache.Close()
+
+	cache.SetWithTTL("a", 1, time.Minute)
+	cache.SetWithTTL("b", 2, time.Hour)
+	if _, exp, _ := cache.GetWithExpiration("a"); !exp.Equal(clock.Now().Add(time.Minute)) {
+		t.Errorf("Expected the expiration to be read from the clock, got %v", exp)
+	}
+
+	clock.Advance(59 * time.Second)
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("Expected key 'a' to be present before its TTL elapses")
+	}
+	clock.Advance(2 * time.Second)
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected key 'a' to have expired once the clock passes its deadline")
+	}
+
+	// The expiration goroutine waits on a timer of the clock.
+	clock.Advance(time.Hour)
+	for _, want := range []string{"a", "b"} {
+		select {
+		case key := <-expired:
+			if key != want {
+				t.Errorf("Expected key %q to expire, got %q", want, key)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected key %q to expire", want)
+		}
+	}
+}
+
+func TestManualClockTimer(t *testing.T) {
+	clock := goutte.NewManualClock(time.Unix(0, 0))
+	timer := clock.NewTimer(time.Second)
+
+	clock.Advance(999 * time.Millisecond)
+	select {
+	case <-timer.C():
+		t.Fatal("Expected the timer not to fire before its deadline")
+	default:
+	}
+	clock.Advance(time.Millisecond)
+	select {
+	case now := <-timer.C():
+		if !now.Equal(time.Unix(1, 0)) {
+			t.Errorf("Expected the timer to fire at 1s, got %v", now)
+		}
+	default:
+		t.Fatal("Expected the timer to fire at its deadline")
+	}
+
+	if timer.Stop() {
+		t.Error("Expected Stop to report a fired timer as inactive")
+	}
+	if timer.Reset(time.Second) {
+		t.Error("Expected Reset to report a fired timer as inactive")
+	}
+	if !timer.Stop() {
+		t.Error("Expected Stop to report a reset timer as active")
+	}
+	clock.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		t.Error("Expected a stopped timer not to fire")
+	default:
+	}
+}
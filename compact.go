@@ -0,0 +1,193 @@
+package goutte
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Index marking the absence of a slot in CompactCache links.
+const noSlot = -1
+
+// LRU cache specialized for small fixed-size values (e.g. [16]byte IDs or pointer-free structs).
+// Entries live in a single contiguous slice and are linked by index, and the map only holds slot
+// indexes, so the cache allocates no object per entry and gives the garbage collector nothing to
+// scan beyond the map and slice when K and V contain no pointers.
+//
+// It trades features for footprint: TTLs are enforced lazily on lookup, with no expiration goroutine,
+// so expired entries keep their slot until they are read or evicted; options, statistics and
+// callbacks of Cache are not available.
+type CompactCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	index    map[K]int32
+	slots    []compactSlot[K, V]
+	head     int32 // most recently used slot
+	tail     int32 // least recently used slot
+	free     int32 // first slot of the free list, chained through next
+}
+
+type compactSlot[K comparable, V any] struct {
+	key        K
+	value      V
+	expiration int64 // Unix nanoseconds; 0 if the entry has no TTL
+	prev, next int32
+}
+
+// Creates a compact cache holding at most capacity entries. Slots are allocated as the cache fills.
+func NewCompactCache[K comparable, V any](capacity int) *CompactCache[K, V] {
+	if capacity <= 0 {
+		panic("capacity must be greater than zero")
+	}
+	if capacity > math.MaxInt32 {
+		panic("compact cache capacity must fit in an int32")
+	}
+	return &CompactCache[K, V]{
+		capacity: capacity,
+		index:    make(map[K]int32),
+		head:     noSlot,
+		tail:     noSlot,
+		free:     noSlot,
+	}
+}
+
+// Retrieves the value associated with the given key, moving it to the front of the list.
+// Returns false if the key is missing or expired.
+func (c *CompactCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i, ok := c.lookup(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.moveToFront(i)
+	return c.slots[i].value, true
+}
+
+// Inserts or updates a key-value pair in the cache without a TTL.
+func (c *CompactCache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, 0)
+}
+
+// Inserts or updates a key-value pair in the cache with an optional TTL.
+// A positive ttl will cause the entry to expire after the given duration.
+func (c *CompactCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	var expiration int64
+	if ttl > 0 {
+		expiration = time.Now().Add(ttl).UnixNano()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if i, ok := c.index[key]; ok {
+		s := &c.slots[i]
+		s.value = value
+		s.expiration = expiration
+		c.moveToFront(i)
+		return
+	}
+
+	if len(c.index) >= c.capacity {
+		c.remove(c.tail)
+	}
+	var i int32
+	if c.free != noSlot {
+		i = c.free
+		c.free = c.slots[i].next
+	} else {
+		i = int32(len(c.slots))
+		c.slots = append(c.slots, compactSlot[K, V]{})
+	}
+	c.slots[i] = compactSlot[K, V]{key: key, value: value, expiration: expiration, prev: noSlot, next: noSlot}
+	c.index[key] = i
+	c.pushFront(i)
+}
+
+// Removes a key from the cache if it exists.
+func (c *CompactCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if i, ok := c.index[key]; ok {
+		c.remove(i)
+	}
+}
+
+// Returns the number of entries held, including expired entries not yet looked up.
+func (c *CompactCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.index)
+}
+
+// Clears all entries from the cache and releases its slots.
+func (c *CompactCache[K, V]) Dump() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.index = make(map[K]int32)
+	c.slots = nil
+	c.head, c.tail, c.free = noSlot, noSlot, noSlot
+}
+
+// Returns the slot of key, removing it and reporting false if it has expired.
+func (c *CompactCache[K, V]) lookup(key K) (int32, bool) {
+	i, ok := c.index[key]
+	if !ok {
+		return noSlot, false
+	}
+	if exp := c.slots[i].expiration; exp != 0 && time.Now().UnixNano() > exp {
+		c.remove(i)
+		return noSlot, false
+	}
+	return i, true
+}
+
+// Unlinks slot i, drops its key from the map and puts the slot on the free list.
+func (c *CompactCache[K, V]) remove(i int32) {
+	c.unlink(i)
+	delete(c.index, c.slots[i].key)
+	// Clear the slot so it retains nothing the garbage collector would have to keep alive.
+	c.slots[i] = compactSlot[K, V]{prev: noSlot, next: c.free}
+	c.free = i
+}
+
+func (c *CompactCache[K, V]) moveToFront(i int32) {
+	if c.head == i {
+		return
+	}
+	c.unlink(i)
+	c.pushFront(i)
+}
+
+func (c *CompactCache[K, V]) pushFront(i int32) {
+	s := &c.slots[i]
+	s.prev = noSlot
+	s.next = c.head
+	if c.head != noSlot {
+		c.slots[c.head].prev = i
+	}
+	c.head = i
+	if c.tail == noSlot {
+		c.tail = i
+	}
+}
+
+func (c *CompactCache[K, V]) unlink(i int32) {
+	s := &c.slots[i]
+	if s.prev != noSlot {
+		c.slots[s.prev].next = s.next
+	} else {
+		c.head = s.next
+	}
+	if s.next != noSlot {
+		c.slots[s.next].prev = s.prev
+	} else {
+		c.tail = s.prev
+	}
+	s.prev, s.next = noSlot, noSlot
+}
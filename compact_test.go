@@ -0,0 +1,86 @@
+package goutte_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCompactCache(t *testing.T) {
+	cache := goutte.NewCompactCache[string, [16]byte](2)
+
+	a, b, c := [16]byte{1}, [16]byte{2}, [16]byte{3}
+	cache.Set("a", a)
+	cache.Set("b", b)
+	if val, ok := cache.Get("a"); !ok || val != a {
+		t.Errorf("Expected key 'a' to have value %v, got %v (found: %v)", a, val, ok)
+	}
+
+	// 'b' is now the least recently used entry.
+	cache.Set("c", c)
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected key 'b' to be evicted")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Expected Len 2, got %d", cache.Len())
+	}
+
+	cache.Set("a", c)
+	if val, _ := cache.Get("a"); val != c {
+		t.Errorf("Expected key 'a' to be updated to %v, got %v", c, val)
+	}
+
+	cache.Delete("a")
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected key 'a' to be deleted")
+	}
+
+	// Freed slots are reused.
+	cache.Set("d", a)
+	cache.Set("e", b)
+	for _, key := range []string{"d", "e"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("Expected key %q to be present", key)
+		}
+	}
+
+	cache.Dump()
+	if cache.Len() != 0 {
+		t.Errorf("Expected an empty cache after Dump, got Len %d", cache.Len())
+	}
+}
+
+func TestCompactCacheTTL(t *testing.T) {
+	cache := goutte.NewCompactCache[int, uint64](2)
+	cache.SetWithTTL(1, 10, 20*time.Millisecond)
+	cache.Set(2, 20)
+
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := cache.Get(1); ok {
+		t.Error("Expected key 1 to be expired")
+	}
+	if cache.Len() != 1 {
+		t.Errorf("Expected the expired entry to be removed on lookup, got Len %d", cache.Len())
+	}
+}
+
+func TestCompactCacheConcurrency(t *testing.T) {
+	cache := goutte.NewCompactCache[int, int](50)
+	var wg sync.WaitGroup
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cache.Set(i, i*10)
+			cache.Get(i)
+		}(i)
+	}
+	wg.Wait()
+
+	if cache.Len() != 50 {
+		t.Errorf("Expected Len 50, got %d", cache.Len())
+	}
+}
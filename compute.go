@@ -0,0 +1,143 @@
+package goutte
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Computation of a missing key shared by every caller that misses on it while it runs.
+type flight[V any] struct {
+	done  chan struct{} // closed once val and err are set
+	val   V
+	err   error
+	stale bool // the key was written or deleted meanwhile; guarded by c.mu
+
+	waiters atomic.Int32 // callers blocked on done, see Stats.Waiters
+}
+
+// Retrieves the value for key, computing and inserting it with compute if it is missing.
+// compute runs without the cache lock held, and concurrent callers missing on the same key are
+// coalesced: only one runs compute, and the others wait for its result, including its error.
+// Errors are not cached. If the key is written or deleted while compute runs, the computed value is
+// returned but not stored. The value is stored with the default TTL, if any. While the key is
+// soft-deleted, ErrSoftDeleted is returned and compute is not called.
+func (c *Cache[K, V]) GetOrCompute(key K, compute func() (V, error)) (V, error) {
+	val, _, err := c.coalesce(context.Background(), key, func(context.Context) (V, time.Duration, error) {
+		val, err := compute()
+		return val, -1, err
+	})
+	return val, err
+}
+
+// Looks up key and, on a miss, runs load once for all concurrent callers, storing its result with
+// the returned TTL: none if it is 0, the default TTL if it is negative. Waiters give up with ctx.Err() if ctx is done first; the load itself keeps running.
+// On a hit past the entry's refresh deadline (see WithRefreshAfter), load runs in the background
+// with a context detached from ctx's cancellation, and the current value is returned meanwhile.
+// It reports whether the value was served from the cache.
+func (c *Cache[K, V]) coalesce(ctx context.Context, key K, load func(context.Context) (V, time.Duration, error)) (V, bool, error) {
+	c.lock()
+	if ele := c.lookupLocked(key); ele != nil {
+		c.hitLocked(ele)
+		ent := ele.Value.(*entry[K, V])
+		val := ent.value
+		var refresh *flight[V]
+		if !ent.refresh.IsZero() && c.clock.Now().After(ent.refresh) && c.flights[key] == nil {
+			ent.refresh = time.Time{}
+			refresh = c.startFlightLocked(key)
+		}
+		c.unlock()
+		if refresh != nil {
+			go c.runFlight(context.WithoutCancel(ctx), key, refresh, load, true)
+		}
+		return val, true, nil
+	}
+	c.missLocked(key)
+	if c.tombstonedLocked(key) {
+		c.unlock()
+		var zero V
+		return zero, false, ErrSoftDeleted
+	}
+	if f, ok := c.flights[key]; ok {
+		c.coalesced++
+		f.waiters.Add(1)
+		defer f.waiters.Add(-1)
+		c.unlock()
+		select {
+		case <-f.done:
+			return f.val, false, f.err
+		case <-ctx.Done():
+			var zero V
+			return zero, false, ctx.Err()
+		}
+	}
+	f := c.startFlightLocked(key)
+	c.unlock()
+
+	c.runFlight(ctx, key, f, load, false)
+	return f.val, false, f.err
+}
+
+// Registers a new flight for key.
+func (c *Cache[K, V]) startFlightLocked(key K) *flight[V] {
+	f := &flight[V]{done: make(chan struct{})}
+	if c.flights == nil {
+		c.flights = make(map[K]*flight[V])
+	}
+	c.flights[key] = f
+	return f
+}
+
+// Runs load for a flight, stores its result unless it failed or went stale, and releases the waiters.
+// If load panics, waiters receive an error and the panic propagates to the caller, unless the flight
+// is a background refresh: no caller could recover the panic then, so it fails the refresh like an
+// error, keeping the current value.
+func (c *Cache[K, V]) runFlight(ctx context.Context, key K, f *flight[V], load func(context.Context) (V, time.Duration, error), background bool) {
+	var ttl time.Duration
+	completed := false
+	defer func() {
+		switch {
+		case completed:
+		case background:
+			f.err = fmt.Errorf("goutte: refreshing key %v panicked: %v", key, recover())
+		default:
+			f.err = fmt.Errorf("goutte: computing key %v panicked", key)
+		}
+		c.lock()
+		delete(c.flights, key)
+		switch {
+		case f.err == nil && !f.stale:
+			if ttl < 0 {
+				ttl = c.defaultTTL
+			}
+			c.writeLocked(key, f.val, ttl)
+			c.armRefreshLocked(key)
+		case !f.stale:
+			// A failed refresh is retried on a later hit.
+			c.armRefreshLocked(key)
+		}
+		c.unlock()
+		close(f.done)
+	}()
+
+	f.val, ttl, f.err = load(ctx)
+	completed = true
+}
+
+// Sets the refresh deadline of the entry for key, if refresh-ahead is enabled and the entry is live.
+func (c *Cache[K, V]) armRefreshLocked(key K) {
+	if c.refreshAfter <= 0 {
+		return
+	}
+	if ele := c.lookupLocked(key); ele != nil {
+		ele.Value.(*entry[K, V]).refresh = c.clock.Now().Add(c.refreshAfter)
+	}
+}
+
+// Marks the in-flight computation of key, if any, as stale so its result is not stored.
+func (c *Cache[K, V]) invalidateFlightLocked(key K) {
+	if f, ok := c.flights[key]; ok {
+		f.stale = true
+	}
+}
@@ -0,0 +1,117 @@
+package goutte_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheGetOrCompute(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+
+	var calls atomic.Int32
+	compute := func() (int, error) {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if val, err := cache.GetOrCompute("answer", compute); err != nil || val != 42 {
+				t.Errorf("Expected 42, got %d (err: %v)", val, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected a single computation, got %d", n)
+	}
+	if val, ok := cache.Get("answer"); !ok || val != 42 {
+		t.Errorf("Expected the computed value to be cached, got %d (found: %v)", val, ok)
+	}
+}
+
+func TestCacheGetOrComputeError(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+
+	errBoom := errors.New("boom")
+	if _, err := cache.GetOrCompute("a", func() (int, error) { return 0, errBoom }); !errors.Is(err, errBoom) {
+		t.Errorf("Expected the compute error, got %v", err)
+	}
+	if cache.Contains("a") {
+		t.Error("Expected a failed computation not to be cached")
+	}
+
+	// A panicking computation releases the key for the next caller.
+	func() {
+		defer func() { recover() }()
+		cache.GetOrCompute("a", func() (int, error) { panic("boom") })
+	}()
+	if val, err := cache.GetOrCompute("a", func() (int, error) { return 1, nil }); err != nil || val != 1 {
+		t.Errorf("Expected 1 after the panic, got %d (err: %v)", val, err)
+	}
+}
+
+func TestCacheGetOrComputeCoalescing(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+
+	// Waiters receive the error of the shared computation.
+	errBoom := errors.New("boom")
+	var calls atomic.Int32
+	release := make(chan struct{})
+	compute := func() (int, error) {
+		calls.Add(1)
+		<-release
+		return 0, errBoom
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.GetOrCompute("a", compute); !errors.Is(err, errBoom) {
+				t.Errorf("Expected the shared error, got %v", err)
+			}
+		}()
+	}
+	for cache.Stats().Coalesced < 4 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected a single computation, got %d", n)
+	}
+
+	// A write during the computation wins over its result.
+	started := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.GetOrCompute("b", func() (int, error) {
+			close(started)
+			time.Sleep(20 * time.Millisecond)
+			return 1, nil
+		})
+	}()
+	<-started
+	cache.Set("b", 2)
+	<-done
+	if val, _ := cache.Get("b"); val != 2 {
+		t.Errorf("Expected the concurrent write to be kept, got %d", val)
+	}
+}
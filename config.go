@@ -0,0 +1,426 @@
+package goutte
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Serializable cache settings, so tuning can change without recompiling; see NewFromConfig and
+// NewShardedFromConfig. Zero fields leave the corresponding feature disabled or at its default.
+type Config struct {
+	Capacity int `json:"capacity"`
+
+	// Promotion strategy on hits: "always" (default), "every:N", "cold-half" or "probability:P".
+	Promotion string `json:"promotion,omitempty"`
+
+	// Eviction policy: "lru" (default), "2q", "2q:IN,OUT", "slru", "slru:PROTECTED", "clock", "fifo",
+	// "random" or "mru". 2Q defaults to 0.25,0.5 and SLRU to 0.8; see WithPolicy.
+	Policy string `json:"policy,omitempty"`
+
+	// Bound on the total weight of the entries, see WithWeigher: entries weigh 1 unless written with
+	// SetWithCost, or weighed by a weigher passed in the options. MaxMemory bounds their estimated size
+	// in bytes instead, see WithMaxMemory; at most one of them may be set.
+	MaxWeight int64 `json:"max_weight,omitempty"`
+	MaxMemory int64 `json:"max_memory,omitempty"`
+
+	// Number of shards of a cache created by NewShardedFromConfig, a power of two; 0 scales it to
+	// GOMAXPROCS. Capacity, MaxWeight and MaxMemory are split between the shards.
+	Shards int `json:"shards,omitempty"`
+
+	DefaultTTL    Duration `json:"default_ttl,omitempty"`
+	MinResidency  Duration `json:"min_residency,omitempty"`
+	RankHistogram bool     `json:"rank_histogram,omitempty"`
+
+	// Sizes of the bounded diagnostics buffers and tables; 0 disables them.
+	KeyStats        int `json:"key_stats,omitempty"`
+	RecentMisses    int `json:"recent_misses,omitempty"`
+	EventLog        int `json:"event_log,omitempty"`
+	MaxCallerLabels int `json:"max_caller_labels,omitempty"`
+
+	// See JanitorConfig.
+	JanitorBatchSize        int `json:"janitor_batch_size,omitempty"`
+	JanitorBacklogThreshold int `json:"janitor_backlog_threshold,omitempty"`
+	JanitorSetCleanup       int `json:"janitor_set_cleanup,omitempty"`
+}
+
+// time.Duration that reads and writes JSON as a string such as "1m30s". Plain numbers are accepted
+// as nanoseconds.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		var n int64
+		if err := json.Unmarshal(b, &n); err != nil {
+			return fmt.Errorf("duration must be a string or a number of nanoseconds: %s", b)
+		}
+		*d = Duration(n)
+		return nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Invalid Config field, as returned by Validate and NewFromConfig. It matches ErrInvalidConfig with errors.Is.
+type ConfigError struct {
+	Field  string // JSON name of the field
+	Reason string
+}
+
+func (e *ConfigError) Error() string {
+	return "goutte: invalid config: " + e.Field + ": " + e.Reason
+}
+
+func (e *ConfigError) Unwrap() error {
+	return ErrInvalidConfig
+}
+
+// Checks the settings without creating a cache, returning a *ConfigError for the first invalid field.
+func (cfg Config) Validate() error {
+	if cfg.Capacity <= 0 {
+		return &ConfigError{Field: "capacity", Reason: "must be greater than zero"}
+	}
+	if _, err := parsePromotion(cfg.Promotion); err != nil {
+		return err
+	}
+	if _, err := parsePolicy(cfg.Policy); err != nil {
+		return err
+	}
+	checks := []struct {
+		field string
+		value int64
+	}{
+		{"default_ttl", int64(cfg.DefaultTTL)},
+		{"min_residency", int64(cfg.MinResidency)},
+		{"key_stats", int64(cfg.KeyStats)},
+		{"recent_misses", int64(cfg.RecentMisses)},
+		{"event_log", int64(cfg.EventLog)},
+		{"janitor_batch_size", int64(cfg.JanitorBatchSize)},
+		{"janitor_backlog_threshold", int64(cfg.JanitorBacklogThreshold)},
+		{"janitor_set_cleanup", int64(cfg.JanitorSetCleanup)},
+		{"max_weight", cfg.MaxWeight},
+		{"max_memory", cfg.MaxMemory},
+		{"shards", int64(cfg.Shards)},
+	}
+	for _, check := range checks {
+		if check.value < 0 {
+			return &ConfigError{Field: check.field, Reason: "must not be negative"}
+		}
+	}
+	if cfg.MaxCallerLabels == 1 || cfg.MaxCallerLabels < 0 {
+		return &ConfigError{Field: "max_caller_labels", Reason: "must be 0 or at least 2"}
+	}
+	if cfg.MaxWeight > 0 && cfg.MaxMemory > 0 {
+		return &ConfigError{Field: "max_memory", Reason: "cannot be set along with max_weight"}
+	}
+	if cfg.Shards&(cfg.Shards-1) != 0 {
+		return &ConfigError{Field: "shards", Reason: "must be a power of two"}
+	}
+	return nil
+}
+
+func parsePolicy(s string) (Policy, error) {
+	name, arg, hasArg := strings.Cut(s, ":")
+	invalid := func(reason string) (Policy, error) {
+		return Policy{}, &ConfigError{Field: "policy", Reason: reason}
+	}
+	ratio := func(s string, upper float64) (float64, bool) {
+		r, err := strconv.ParseFloat(s, 64)
+		return r, err == nil && r > 0 && r < upper
+	}
+	switch {
+	case (name == "" || name == "lru") && !hasArg:
+		return PolicyLRU(), nil
+	case name == "2q" && !hasArg:
+		return Policy2Q(0.25, 0.5), nil
+	case name == "2q":
+		in, out, _ := strings.Cut(arg, ",")
+		inRatio, okIn := ratio(in, 1)
+		outRatio, okOut := ratio(out, math.Inf(1))
+		if !okIn || !okOut {
+			return invalid("2q:IN,OUT requires IN in (0, 1) and OUT greater than zero")
+		}
+		return Policy2Q(inRatio, outRatio), nil
+	case name == "slru" && !hasArg:
+		return PolicySLRU(0.8), nil
+	case name == "slru":
+		protected, ok := ratio(arg, 1)
+		if !ok {
+			return invalid("slru:PROTECTED requires a number in (0, 1)")
+		}
+		return PolicySLRU(protected), nil
+	case name == "clock" && !hasArg:
+		return PolicyClock(), nil
+	case name == "fifo" && !hasArg:
+		return PolicyFIFO(), nil
+	case name == "random" && !hasArg:
+		return PolicyRandom(), nil
+	case name == "mru" && !hasArg:
+		return PolicyMRU(), nil
+	}
+	return invalid(fmt.Sprintf("unknown policy %q", s))
+}
+
+func parsePromotion(s string) (Promotion, error) {
+	name, arg, hasArg := strings.Cut(s, ":")
+	invalid := func(reason string) (Promotion, error) {
+		return Promotion{}, &ConfigError{Field: "promotion", Reason: reason}
+	}
+	switch {
+	case (name == "" || name == "always") && !hasArg:
+		return PromoteAlways(), nil
+	case name == "cold-half" && !hasArg:
+		return PromoteColdHalf(), nil
+	case name == "every" && hasArg:
+		n, err := strconv.Atoi(arg)
+		if err != nil || n <= 0 {
+			return invalid("every:N requires a positive integer")
+		}
+		return PromoteEveryN(n), nil
+	case name == "probability" && hasArg:
+		p, err := strconv.ParseFloat(arg, 64)
+		if err != nil || p <= 0 || p > 1 {
+			return invalid("probability:P requires a number in (0, 1]")
+		}
+		return PromoteWithProbability(p), nil
+	}
+	return invalid(fmt.Sprintf("unknown strategy %q", s))
+}
+
+// Creates a cache from cfg, after validating it. Options that cannot be serialized, such as callbacks,
+// can be passed in opts and are applied after those derived from cfg. The effective configuration is
+// reported by Stats. Shards must not be set; see NewShardedFromConfig.
+func NewFromConfig[K comparable, V any](cfg Config, opts ...Option[K, V]) (*Cache[K, V], error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Shards > 0 {
+		return nil, &ConfigError{Field: "shards", Reason: "requires NewShardedFromConfig"}
+	}
+	return NewCache(cfg.Capacity, append(configOptions[K, V](cfg), opts...)...), nil
+}
+
+// Creates a sharded cache from cfg, after validating it, like NewFromConfig. Capacity, MaxWeight and
+// MaxMemory are split between the shards, as NewShardedCache does with the capacity; the other
+// settings and opts apply to every shard.
+func NewShardedFromConfig[K comparable, V any](cfg Config, opts ...Option[K, V]) (*ShardedCache[K, V], error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	n := shardCount(cfg.Shards, cfg.Capacity)
+	shard := cfg
+	shard.Shards = 0
+	shard.Capacity = (cfg.Capacity + n - 1) / n
+	shard.MaxWeight = (cfg.MaxWeight + int64(n) - 1) / int64(n)
+	shard.MaxMemory = (cfg.MaxMemory + int64(n) - 1) / int64(n)
+	shardOpts := append(configOptions[K, V](shard), opts...)
+	return NewShardedCache(cfg.Capacity, WithShardCount[K, V](n), WithShardOptions(shardOpts...)), nil
+}
+
+// Returns the options applying a validated cfg.
+func configOptions[K comparable, V any](cfg Config) []Option[K, V] {
+	promotion, _ := parsePromotion(cfg.Promotion)
+	policy, _ := parsePolicy(cfg.Policy)
+
+	var cfgOpts []Option[K, V]
+	cfgOpts = append(cfgOpts, WithPromotion[K, V](promotion), WithPolicy[K, V](policy))
+	if cfg.MaxWeight > 0 {
+		cfgOpts = append(cfgOpts, WithWeigher[K, V](cfg.MaxWeight, nil))
+	}
+	if cfg.MaxMemory > 0 {
+		cfgOpts = append(cfgOpts, WithMaxMemory[K, V](cfg.MaxMemory))
+	}
+	if cfg.DefaultTTL > 0 {
+		cfgOpts = append(cfgOpts, WithDefaultTTL[K, V](time.Duration(cfg.DefaultTTL)))
+	}
+	if cfg.MinResidency > 0 {
+		cfgOpts = append(cfgOpts, WithMinResidency[K, V](time.Duration(cfg.MinResidency)))
+	}
+	if cfg.RankHistogram {
+		cfgOpts = append(cfgOpts, WithRankHistogram[K, V]())
+	}
+	if cfg.KeyStats > 0 {
+		cfgOpts = append(cfgOpts, WithKeyStats[K, V](cfg.KeyStats, nil))
+	}
+	if cfg.RecentMisses > 0 {
+		cfgOpts = append(cfgOpts, WithRecentMisses[K, V](cfg.RecentMisses, nil))
+	}
+	if cfg.EventLog > 0 {
+		cfgOpts = append(cfgOpts, WithEventLog[K, V](cfg.EventLog))
+	}
+	if cfg.MaxCallerLabels > 0 {
+		cfgOpts = append(cfgOpts, WithMaxCallerLabels[K, V](cfg.MaxCallerLabels))
+	}
+	if cfg.JanitorBatchSize > 0 || cfg.JanitorBacklogThreshold > 0 || cfg.JanitorSetCleanup > 0 {
+		cfgOpts = append(cfgOpts, WithJanitor[K, V](JanitorConfig{
+			BatchSize:        cfg.JanitorBatchSize,
+			BacklogThreshold: cfg.JanitorBacklogThreshold,
+			SetCleanup:       cfg.JanitorSetCleanup,
+		}))
+	}
+	return append(cfgOpts, func(c *Cache[K, V]) {
+		c.config = cfg
+	})
+}
+
+// Decodes a JSON Config from r. Unknown fields are rejected so typos do not go unnoticed.
+func LoadConfigJSON(r io.Reader) (Config, error) {
+	var cfg Config
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, fmt.Errorf("goutte: decoding config: %w", err)
+	}
+	return cfg, cfg.Validate()
+}
+
+// Overrides the fields of base set in the environment. Each field is read from the variable named
+// after its JSON name, upper-cased and prefixed, e.g. APP_CACHE_MIN_RESIDENCY for the prefix
+// "APP_CACHE_". Durations use time.ParseDuration syntax.
+func LoadConfigEnv(prefix string, base Config) (Config, error) {
+	cfg := base
+	v := reflect.ValueOf(&cfg).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		raw, ok := os.LookupEnv(prefix + strings.ToUpper(name))
+		if !ok {
+			continue
+		}
+		field := v.Field(i)
+		var err error
+		switch field.Interface().(type) {
+		case Duration:
+			var d time.Duration
+			if d, err = time.ParseDuration(raw); err == nil {
+				field.SetInt(int64(d))
+			}
+		case int:
+			var n int
+			if n, err = strconv.Atoi(raw); err == nil {
+				field.SetInt(int64(n))
+			}
+		case int64:
+			var n int64
+			if n, err = strconv.ParseInt(raw, 10, 64); err == nil {
+				field.SetInt(n)
+			}
+		case bool:
+			var b bool
+			if b, err = strconv.ParseBool(raw); err == nil {
+				field.SetBool(b)
+			}
+		case string:
+			field.SetString(raw)
+		}
+		if err != nil {
+			return Config{}, &ConfigError{Field: name, Reason: fmt.Sprintf("cannot parse %q from the environment", raw)}
+		}
+	}
+	return cfg, cfg.Validate()
+}
+
+// Applies new settings to a live cache without losing its contents, e.g. from a feature-flag system
+// during an incident. Capacity (evicting as SetCapacity does), Promotion, DefaultTTL, MinResidency and
+// the janitor settings can change; DefaultTTL and MinResidency only apply to later writes. The other
+// fields size structures allocated at creation and must match the effective config reported by Stats,
+// whether the cache was built by NewFromConfig or by NewCache with options, otherwise a *ConfigError
+// is returned and nothing is changed.
+func (c *Cache[K, V]) Reconfigure(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	promotion, _ := parsePromotion(cfg.Promotion)
+	policy, _ := parsePolicy(cfg.Policy)
+
+	c.lock()
+	defer c.unlock()
+
+	if policy != c.policy {
+		return &ConfigError{Field: "policy", Reason: "cannot be changed at runtime"}
+	}
+	current := c.effectiveConfigLocked()
+	if cfg.MaxCallerLabels == defaultMaxCallerLabels {
+		cfg.MaxCallerLabels = 0
+	}
+	fixed := []struct {
+		field        string
+		current, new any
+	}{
+		{"rank_histogram", current.RankHistogram, cfg.RankHistogram},
+		{"key_stats", current.KeyStats, cfg.KeyStats},
+		{"recent_misses", current.RecentMisses, cfg.RecentMisses},
+		{"event_log", current.EventLog, cfg.EventLog},
+		{"max_caller_labels", current.MaxCallerLabels, cfg.MaxCallerLabels},
+		{"max_weight", current.MaxWeight, cfg.MaxWeight},
+		{"max_memory", current.MaxMemory, cfg.MaxMemory},
+		{"shards", current.Shards, cfg.Shards},
+	}
+	for _, f := range fixed {
+		if f.current != f.new {
+			return &ConfigError{Field: f.field, Reason: "cannot be changed at runtime"}
+		}
+	}
+
+	c.promotion = promotion
+	c.defaultTTL = time.Duration(cfg.DefaultTTL)
+	c.minResidency = time.Duration(cfg.MinResidency)
+	c.janitor.BatchSize = cfg.JanitorBatchSize
+	c.janitor.BacklogThreshold = cfg.JanitorBacklogThreshold
+	c.janitor.SetCleanup = cfg.JanitorSetCleanup
+	if c.janitor.SetCleanup == 0 {
+		c.janitor.SetCleanup = defaultSetCleanup
+	}
+	if c.janitor.BacklogThreshold == 0 {
+		c.janitor.degraded = false
+	}
+	c.config = cfg
+	c.setCapacityLocked(cfg.Capacity)
+	// Wake the janitor so a new batch size applies to the next pass.
+	c.signalExpirationUpdate()
+	return nil
+}
+
+// Returns the settings of the cache, read from the structures they configure, so that caches built
+// with options report them too. Promotion, Policy and the janitor settings are those last set from a
+// Config, and a weight bound set by options is reported as MaxWeight.
+func (c *Cache[K, V]) effectiveConfigLocked() Config {
+	cfg := c.config
+	cfg.Capacity = c.capacity
+	cfg.DefaultTTL = Duration(c.defaultTTL)
+	cfg.MinResidency = Duration(c.minResidency)
+	cfg.RankHistogram = c.ranks != nil
+	if cfg.MaxMemory == 0 {
+		cfg.MaxWeight = c.maxWeight
+	}
+	cfg.KeyStats, cfg.RecentMisses, cfg.EventLog, cfg.MaxCallerLabels = 0, 0, 0, 0
+	if c.keyStats != nil {
+		cfg.KeyStats = c.keyStats.size
+	}
+	if c.missLog != nil {
+		cfg.RecentMisses = len(c.missLog.buf)
+	}
+	if c.events != nil {
+		cfg.EventLog = len(c.events.buf)
+	}
+	// 0 stands for the default limit.
+	if n := c.labels.max; n != defaultMaxCallerLabels {
+		cfg.MaxCallerLabels = n
+	}
+	return cfg
+}
@@ -0,0 +1,221 @@
+package goutte_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestNewFromConfig(t *testing.T) {
+	cfg, err := goutte.LoadConfigJSON(strings.NewReader(`{
+		"capacity": 2,
+		"promotion": "every:2",
+		"min_residency": "1ms",
+		"event_log": 8
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.MinResidency != goutte.Duration(time.Millisecond) {
+		t.Errorf("Expected min_residency to parse as 1ms, got %v", time.Duration(cfg.MinResidency))
+	}
+
+	cache, err := goutte.NewFromConfig[string, int](cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer cache.Close()
+	cache.Set("a", 1)
+	if events := cache.RecentEvents(nil); len(events) != 1 {
+		t.Errorf("Expected the event log to be enabled, got %+v", events)
+	}
+
+	cache.SetCapacity(3)
+	stats := cache.Stats()
+	if stats.Promotion != "every 2 hits" {
+		t.Errorf("Expected the configured promotion strategy, got %q", stats.Promotion)
+	}
+	cfg.Capacity = 3
+	if stats.Config != cfg {
+		t.Errorf("Expected effective config %+v, got %+v", cfg, stats.Config)
+	}
+}
+
+func TestConfigValidation(t *testing.T) {
+	cases := map[string]goutte.Config{
+		"capacity":          {},
+		"promotion":         {Capacity: 1, Promotion: "every:0"},
+		"event_log":         {Capacity: 1, EventLog: -1},
+		"max_caller_labels": {Capacity: 1, MaxCallerLabels: 1},
+	}
+	for field, cfg := range cases {
+		_, err := goutte.NewFromConfig[string, int](cfg)
+		var cfgErr *goutte.ConfigError
+		if !errors.As(err, &cfgErr) || cfgErr.Field != field || !errors.Is(err, goutte.ErrInvalidConfig) {
+			t.Errorf("Expected a config error on %q, got %v", field, err)
+		}
+	}
+
+	if _, err := goutte.LoadConfigJSON(strings.NewReader(`{"capacity": 1, "capacty": 2}`)); err == nil {
+		t.Error("Expected unknown fields to be rejected")
+	}
+}
+
+func TestLoadConfigEnv(t *testing.T) {
+	t.Setenv("TEST_CACHE_CAPACITY", "64")
+	t.Setenv("TEST_CACHE_MIN_RESIDENCY", "2s")
+	t.Setenv("TEST_CACHE_RANK_HISTOGRAM", "true")
+	t.Setenv("TEST_CACHE_MAX_WEIGHT", "4096")
+
+	cfg, err := goutte.LoadConfigEnv("TEST_CACHE_", goutte.Config{Capacity: 8, KeyStats: 16})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := goutte.Config{Capacity: 64, MinResidency: goutte.Duration(2 * time.Second), RankHistogram: true, KeyStats: 16, MaxWeight: 4096}
+	if cfg != want {
+		t.Errorf("Expected %+v, got %+v", want, cfg)
+	}
+
+	t.Setenv("TEST_CACHE_EVENT_LOG", "lots")
+	if _, err := goutte.LoadConfigEnv("TEST_CACHE_", goutte.Config{}); !errors.Is(err, goutte.ErrInvalidConfig) {
+		t.Errorf("Expected an invalid config error, got %v", err)
+	}
+}
+
+func TestCacheReconfigure(t *testing.T) {
+	cache, err := goutte.NewFromConfig[string, int](goutte.Config{Capacity: 3, EventLog: 4})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+
+	cfg := cache.Stats().Config
+	cfg.Capacity = 2
+	cfg.Promotion = "cold-half"
+	cfg.JanitorBatchSize = 16
+	if err := cache.Reconfigure(cfg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cache.Len() != 2 || cache.Contains("a") {
+		t.Errorf("Expected the shrunk capacity to evict 'a', got keys %v", slices.Collect(cache.Keys()))
+	}
+	if stats := cache.Stats(); stats.Promotion != "cold half" || stats.Config != cfg {
+		t.Errorf("Expected the new settings to be reported, got %+v", stats)
+	}
+
+	fixed := cfg
+	fixed.EventLog = 8
+	var cfgErr *goutte.ConfigError
+	if err := cache.Reconfigure(fixed); !errors.As(err, &cfgErr) || cfgErr.Field != "event_log" {
+		t.Errorf("Expected event_log to be rejected, got %v", err)
+	}
+	if cache.Stats().Config != cfg {
+		t.Error("Expected a rejected Reconfigure to leave the settings unchanged")
+	}
+}
+
+func TestCacheReconfigureOptions(t *testing.T) {
+	cache := goutte.NewCache(3,
+		goutte.WithEventLog[string, int](8),
+		goutte.WithRankHistogram[string, int](),
+		goutte.WithDefaultTTL[string, int](time.Minute))
+	defer cache.Close()
+
+	cfg := cache.Stats().Config
+	want := goutte.Config{Capacity: 3, EventLog: 8, RankHistogram: true, DefaultTTL: goutte.Duration(time.Minute)}
+	if cfg != want {
+		t.Errorf("Expected the settings of the options %+v, got %+v", want, cfg)
+	}
+
+	// The features enabled by options are kept...
+	cfg.Capacity = 5
+	if err := cache.Reconfigure(cfg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// ... and cannot be disabled.
+	var cfgErr *goutte.ConfigError
+	if err := cache.Reconfigure(goutte.Config{Capacity: 5, RankHistogram: true}); !errors.As(err, &cfgErr) || cfgErr.Field != "event_log" {
+		t.Errorf("Expected disabling event_log to be rejected, got %v", err)
+	}
+	if cache.Capacity() != 5 {
+		t.Errorf("Expected capacity 5, got %d", cache.Capacity())
+	}
+}
+
+func TestNewFromConfigPolicyAndWeight(t *testing.T) {
+	cfg, err := goutte.LoadConfigJSON(strings.NewReader(`{"capacity": 10, "policy": "2q:0.5,1", "max_weight": 5}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cache, err := goutte.NewFromConfig[string, int](cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer cache.Close()
+
+	cache.SetWithCost("a", 1, 4, 0)
+	cache.SetWithCost("b", 2, 3, 0)
+	stats := cache.Stats()
+	if stats.Policy != "2q in=0.5 out=1" {
+		t.Errorf("Expected the configured policy, got %q", stats.Policy)
+	}
+	if stats.Weight != 3 || cache.Contains("a") {
+		t.Errorf("Expected 'a' to be evicted to respect max_weight, got weight %d", stats.Weight)
+	}
+	if stats.Config != cfg {
+		t.Errorf("Expected effective config %+v, got %+v", cfg, stats.Config)
+	}
+}
+
+func TestConfigValidationPolicyAndShards(t *testing.T) {
+	cases := map[string]goutte.Config{
+		"policy":     {Capacity: 1, Policy: "lfu"},
+		"max_memory": {Capacity: 1, MaxWeight: 10, MaxMemory: 10},
+		"shards":     {Capacity: 1, Shards: 3},
+	}
+	for field, cfg := range cases {
+		_, err := goutte.NewShardedFromConfig[string, int](cfg)
+		var cfgErr *goutte.ConfigError
+		if !errors.As(err, &cfgErr) || cfgErr.Field != field {
+			t.Errorf("Expected a config error on %q, got %v", field, err)
+		}
+	}
+	for _, policy := range []string{"", "lru", "2q", "slru", "slru:0.5", "clock", "fifo", "random", "mru"} {
+		if err := (goutte.Config{Capacity: 1, Policy: policy}).Validate(); err != nil {
+			t.Errorf("Expected policy %q to be valid, got %v", policy, err)
+		}
+	}
+	if _, err := goutte.NewFromConfig[string, int](goutte.Config{Capacity: 8, Shards: 2}); err == nil {
+		t.Error("Expected NewFromConfig to reject shards")
+	}
+}
+
+func TestNewShardedFromConfig(t *testing.T) {
+	cfg := goutte.Config{Capacity: 64, Shards: 4, MaxWeight: 40, Policy: "fifo", EventLog: 8}
+	cache, err := goutte.NewShardedFromConfig[int, int](cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer cache.Close()
+
+	for i := 0; i < 64; i++ {
+		cache.Set(i, i)
+	}
+	stats := cache.Stats()
+	if stats.Config != cfg {
+		t.Errorf("Expected effective config %+v, got %+v", cfg, stats.Config)
+	}
+	if stats.Policy != "fifo" {
+		t.Errorf("Expected the configured policy, got %q", stats.Policy)
+	}
+	if stats.Weight > 40 {
+		t.Errorf("Expected max_weight to bound the total weight, got %d", stats.Weight)
+	}
+}
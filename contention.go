@@ -0,0 +1,58 @@
+package goutte
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Lock contention measurements for a cache.
+// Counters are only collected when the package is built with the goutte_contention tag;
+// otherwise lock acquisition is not instrumented and all values stay zero.
+type ContentionStats struct {
+	Acquisitions uint64        // number of times the cache lock was acquired
+	Contended    uint64        // acquisitions that had to wait for another holder
+	TotalWait    time.Duration // cumulative time spent waiting for the lock
+	MaxWait      time.Duration // longest single wait
+}
+
+// Atomic counters behind ContentionStats.
+type contentionCounters struct {
+	acquisitions atomic.Uint64
+	contended    atomic.Uint64
+	totalWait    atomic.Int64
+	maxWait      atomic.Int64
+}
+
+func (cc *contentionCounters) record(wait time.Duration) {
+	cc.acquisitions.Add(1)
+	if wait <= 0 {
+		return
+	}
+	cc.contended.Add(1)
+	cc.totalWait.Add(int64(wait))
+	for {
+		cur := cc.maxWait.Load()
+		if int64(wait) <= cur || cc.maxWait.CompareAndSwap(cur, int64(wait)) {
+			return
+		}
+	}
+}
+
+// Zeroes the counters, see ResetStats. Acquisitions concurrent with the reset, such as shared reads,
+// may be counted either before or after it.
+func (cc *contentionCounters) reset() {
+	cc.acquisitions.Store(0)
+	cc.contended.Store(0)
+	cc.totalWait.Store(0)
+	cc.maxWait.Store(0)
+}
+
+// Returns a snapshot of the lock contention counters, also reported by Stats.
+func (c *Cache[K, V]) ContentionStats() ContentionStats {
+	return ContentionStats{
+		Acquisitions: c.contention.acquisitions.Load(),
+		Contended:    c.contention.contended.Load(),
+		TotalWait:    time.Duration(c.contention.totalWait.Load()),
+		MaxWait:      time.Duration(c.contention.maxWait.Load()),
+	}
+}
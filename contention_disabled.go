@@ -0,0 +1,14 @@
+//go:build !goutte_contention
+
+package goutte
+
+// Acquires the cache lock.
+// Build with the goutte_contention tag to record lock wait times.
+func (c *Cache[K, V]) lock() {
+	c.mu.Lock()
+}
+
+// Acquires the cache lock for reading, see WithSharedReads.
+func (c *Cache[K, V]) rlock() {
+	c.mu.RLock()
+}
@@ -0,0 +1,38 @@
+//go:build goutte_contention
+
+package goutte
+
+import (
+	"context"
+	"runtime/trace"
+	"time"
+)
+
+// Acquires the cache lock, recording how long it had to wait.
+// Contended acquisitions are wrapped in a runtime/trace region so they show up in execution traces.
+func (c *Cache[K, V]) lock() {
+	if c.mu.TryLock() {
+		c.contention.record(0)
+		return
+	}
+	region := trace.StartRegion(context.Background(), "goutte.lock")
+	start := time.Now()
+	c.mu.Lock()
+	wait := time.Since(start)
+	region.End()
+	c.contention.record(wait)
+}
+
+// Acquires the cache lock for reading, recording how long it had to wait, like lock.
+func (c *Cache[K, V]) rlock() {
+	if c.mu.TryRLock() {
+		c.contention.record(0)
+		return
+	}
+	region := trace.StartRegion(context.Background(), "goutte.rlock")
+	start := time.Now()
+	c.mu.RLock()
+	wait := time.Since(start)
+	region.End()
+	c.contention.record(wait)
+}
@@ -0,0 +1,52 @@
+//go:build goutte_contention
+
+package goutte_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheContentionStats(t *testing.T) {
+	cache := goutte.NewCache[int, int](100)
+	defer cache.Close()
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				cache.Set(j, i)
+				cache.Get(j)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	stats := cache.ContentionStats()
+	if stats.Acquisitions < 50*100*2 {
+		t.Errorf("Expected at least %d lock acquisitions, got %d", 50*100*2, stats.Acquisitions)
+	}
+	if stats.Contended > 0 && (stats.TotalWait <= 0 || stats.MaxWait <= 0 || stats.MaxWait > stats.TotalWait) {
+		t.Errorf("Inconsistent wait counters: %+v", stats)
+	}
+}
+
+func TestCacheStatsContention(t *testing.T) {
+	cache := goutte.NewCache[int, int](100)
+	defer cache.Close()
+	for i := 0; i < 10; i++ {
+		cache.Set(i, i)
+	}
+
+	// The snapshots count the acquisition of the lock they are taken under.
+	if stats := cache.ResetStats(); stats.Contention.Acquisitions != 11 {
+		t.Errorf("Expected Stats to report 11 lock acquisitions, got %+v", stats.Contention)
+	}
+	if stats := cache.Stats(); stats.Contention.Acquisitions != 1 {
+		t.Errorf("Expected ResetStats to zero the contention counters, got %+v", stats.Contention)
+	}
+}
@@ -0,0 +1,32 @@
+package goutte
+
+// Value types supported by Increment and Decrement.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Atomically adds delta to the value of key and returns the result.
+// A missing (or expired) key is inserted with value delta and the default TTL, if any; an existing
+// entry keeps its expiration, like the Redis INCRBY command. The entry is moved to the front of the list.
+func Increment[K comparable, V Number](c *Cache[K, V], key K, delta V) V {
+	c.lock()
+	defer c.unlock()
+
+	if ele := c.lookupLocked(key); ele != nil {
+		val := ele.Value.(*entry[K, V]).value + delta
+		c.promoteLocked(ele)
+		c.updateLocked(ele, val)
+		return val
+	}
+
+	c.writeLocked(key, delta, c.defaultTTL)
+	return delta
+}
+
+// Atomically subtracts delta from the value of key and returns the result; see Increment.
+// Unsigned values wrap around below zero.
+func Decrement[K comparable, V Number](c *Cache[K, V], key K, delta V) V {
+	return Increment(c, key, -delta)
+}
@@ -0,0 +1,73 @@
+package goutte_test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheIncrement(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+
+	if n := goutte.Increment(cache, "hits", 5); n != 5 {
+		t.Errorf("Expected a missing counter to start at the delta, got %d", n)
+	}
+	if n := goutte.Decrement(cache, "hits", 2); n != 3 {
+		t.Errorf("Expected 3 after decrementing, got %d", n)
+	}
+
+	// The expiration of an existing counter is kept.
+	cache.SetWithTTL("window", 0, 30*time.Millisecond)
+	goutte.Increment(cache, "window", 1)
+	time.Sleep(50 * time.Millisecond)
+	if cache.Contains("window") {
+		t.Error("Expected the counter to expire with its original TTL")
+	}
+
+	floats := goutte.NewCache[string, float64](1)
+	defer floats.Close()
+	goutte.Increment(floats, "ratio", 0.25)
+	if f := goutte.Increment(floats, "ratio", 0.5); f != 0.75 {
+		t.Errorf("Expected 0.75, got %v", f)
+	}
+}
+
+func TestCacheIncrementConcurrency(t *testing.T) {
+	cache := goutte.NewCache[string, uint64](1)
+	defer cache.Close()
+	var wg sync.WaitGroup
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			goutte.Increment(cache, "counter", 2)
+		}()
+	}
+	wg.Wait()
+
+	if val, _ := cache.Get("counter"); val != 200 {
+		t.Errorf("Expected 200, got %d", val)
+	}
+}
+
+func TestCacheIncrementNotifyReplaced(t *testing.T) {
+	var replaced []int
+	cache := goutte.NewCache(2, goutte.WithOnRemoval(func(_ string, v int, reason goutte.EvictionReason) {
+		if reason == goutte.EvictionReplaced {
+			replaced = append(replaced, v)
+		}
+	}))
+	defer cache.Close()
+
+	goutte.Increment(cache, "a", 1)
+	goutte.Increment(cache, "a", 2)
+	goutte.Decrement(cache, "a", 1)
+	if want := []int{1, 3}; !slices.Equal(replaced, want) {
+		t.Errorf("Expected the previous values %v to be reported as replaced, got %v", want, replaced)
+	}
+}
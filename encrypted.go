@@ -0,0 +1,113 @@
+package goutte
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"time"
+)
+
+// LRU cache that keeps []byte values encrypted in memory with AES-GCM and decrypts them on Get,
+// so a memory dump does not expose cached plaintext. Each value is sealed with a fresh random nonce.
+// Keys are stored in the clear, but authenticated along with their value: a ciphertext copied or
+// swapped under another key fails to decrypt.
+type EncryptedCache[K comparable] struct {
+	cache *Cache[K, []byte]
+	aead  cipher.AEAD
+}
+
+// Creates an encrypted cache with a given capacity.
+// The key must be 16, 24 or 32 bytes long (AES-128, AES-192 or AES-256). If it is nil, a random
+// 32-byte key is generated that never leaves the process, which suits caches that are not shared.
+func NewEncryptedCache[K comparable](capacity int, key []byte, opts ...Option[K, []byte]) (*EncryptedCache[K], error) {
+	if key == nil {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedCache[K]{
+		cache: NewCache(capacity, opts...),
+		aead:  aead,
+	}, nil
+}
+
+// Retrieves and decrypts the value associated with the given key.
+// An error is returned only if the stored ciphertext fails authentication, which indicates corruption
+// or a value that was not sealed for this key.
+func (c *EncryptedCache[K]) Get(key K) ([]byte, bool, error) {
+	sealed, ok := c.cache.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+	n := c.aead.NonceSize()
+	plain, err := c.aead.Open(nil, sealed[:n], sealed[n:], associatedData(key))
+	if err != nil {
+		return nil, false, err
+	}
+	return plain, true, nil
+}
+
+// Encrypts and inserts or updates a key-value pair in the cache, with the default TTL if one is set.
+// An error is returned only if no random nonce could be generated.
+func (c *EncryptedCache[K]) Set(key K, value []byte) error {
+	sealed, err := c.seal(key, value)
+	if err != nil {
+		return err
+	}
+	c.cache.Set(key, sealed)
+	return nil
+}
+
+// Encrypts and inserts or updates a key-value pair in the cache with an optional TTL.
+// An error is returned only if no random nonce could be generated.
+func (c *EncryptedCache[K]) SetWithTTL(key K, value []byte, ttl time.Duration) error {
+	sealed, err := c.seal(key, value)
+	if err != nil {
+		return err
+	}
+	c.cache.SetWithTTL(key, sealed, ttl)
+	return nil
+}
+
+// Encrypts value with a fresh random nonce, which is prepended to the ciphertext, binding it to key.
+func (c *EncryptedCache[K]) seal(key K, value []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(value)+c.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, value, associatedData(key)), nil
+}
+
+// Encodes key as the associated data authenticated with its value: string keys as their bytes, and
+// other keys in their Go syntax representation, which tells apart keys of the same type.
+func associatedData[K comparable](key K) []byte {
+	if s, ok := any(key).(string); ok {
+		return []byte(s)
+	}
+	return fmt.Appendf(nil, "%#v", key)
+}
+
+// Removes a key from the cache if it exists.
+func (c *EncryptedCache[K]) Delete(key K) {
+	c.cache.Delete(key)
+}
+
+// Clears all entries from the cache.
+func (c *EncryptedCache[K]) Dump() {
+	c.cache.Dump()
+}
+
+// Stops the background expiration goroutine.
+func (c *EncryptedCache[K]) Close() {
+	c.cache.Close()
+}
@@ -0,0 +1,72 @@
+package goutte_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestEncryptedCache(t *testing.T) {
+	cache, err := goutte.NewEncryptedCache[string](2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+
+	if err := cache.Set("ssn", []byte("123-45-6789")); err != nil {
+		t.Fatal(err)
+	}
+	if val, ok, err := cache.Get("ssn"); err != nil || !ok || string(val) != "123-45-6789" {
+		t.Errorf("Expected key 'ssn' to decrypt to '123-45-6789', got %q (found: %v, err: %v)", val, ok, err)
+	}
+
+	cache.Delete("ssn")
+	if _, ok, err := cache.Get("ssn"); ok || err != nil {
+		t.Errorf("Expected key 'ssn' to be deleted, got found=%v err=%v", ok, err)
+	}
+}
+
+func TestEncryptedCacheTTL(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 16)
+	cache, err := goutte.NewEncryptedCache[string](2, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+
+	cache.SetWithTTL("a", []byte("1"), 50*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	if _, ok, _ := cache.Get("a"); ok {
+		t.Error("Expected key 'a' to have expired")
+	}
+}
+
+func TestEncryptedCacheInvalidKey(t *testing.T) {
+	if _, err := goutte.NewEncryptedCache[string](2, []byte("short")); err == nil {
+		t.Error("Expected an error for an invalid AES key length")
+	}
+}
+
+func TestEncryptedCacheBindsKey(t *testing.T) {
+	// Capture the underlying cache to tamper with the stored ciphertexts.
+	var raw *goutte.Cache[[2]int, []byte]
+	capture := func(c *goutte.Cache[[2]int, []byte]) { raw = c }
+	cache, err := goutte.NewEncryptedCache[[2]int](4, nil, capture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	cache.Set([2]int{1, 2}, []byte("alice"))
+	cache.Set([2]int{2, 1}, []byte("bob"))
+
+	sealed, _ := raw.Get([2]int{1, 2})
+	raw.Set([2]int{2, 1}, sealed)
+	if val, ok, err := cache.Get([2]int{2, 1}); err == nil {
+		t.Errorf("Expected a ciphertext moved to another key to fail authentication, got %q (found: %v)", val, ok)
+	}
+	if val, _, err := cache.Get([2]int{1, 2}); err != nil || string(val) != "alice" {
+		t.Errorf("Expected key [1 2] to decrypt to 'alice', got %q (err: %v)", val, err)
+	}
+}
@@ -0,0 +1,18 @@
+package goutte
+
+import "errors"
+
+var (
+	// Returned (or used as a panic value) by TryGet and MustGet when the key is missing or expired.
+	ErrNotFound = errors.New("goutte: key not found")
+
+	// Returned by GetOrCompute and LoadingCache.Get, without calling the loader, while the key is
+	// soft-deleted.
+	ErrSoftDeleted = errors.New("goutte: key is soft-deleted")
+
+	// Returned when registering a cache under a name that is already taken.
+	ErrDuplicateName = errors.New("goutte: cache name already registered")
+
+	// Matched by every *ConfigError.
+	ErrInvalidConfig = errors.New("goutte: invalid config")
+)
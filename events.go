@@ -0,0 +1,113 @@
+package goutte
+
+import "time"
+
+// Kind of mutation recorded by WithEventLog and delivered by Subscribe.
+type EventOp int
+
+const (
+	// The key was inserted or updated.
+	OpSet EventOp = iota
+	// The entry left the cache; Event.Reason tells why.
+	OpRemove
+	// The entry was hidden by SoftDelete.
+	OpSoftDelete
+	// The entry was restored by Resurrect.
+	OpResurrect
+)
+
+func (op EventOp) String() string {
+	switch op {
+	case OpSet:
+		return "set"
+	case OpRemove:
+		return "remove"
+	case OpSoftDelete:
+		return "soft-delete"
+	case OpResurrect:
+		return "resurrect"
+	}
+	return "unknown"
+}
+
+// Mutation recorded by WithEventLog or delivered by Subscribe and Watch.
+type Event[K comparable] struct {
+	Op     EventOp
+	Key    K
+	Reason EvictionReason // set for OpRemove only
+	Time   time.Time
+}
+
+// Fixed-size ring buffer of the most recent mutations.
+type eventLog[K comparable] struct {
+	buf  []Event[K]
+	next int // index of the slot to overwrite next
+	full bool
+}
+
+func (l *eventLog[K]) record(e Event[K]) {
+	l.buf[l.next] = e
+	l.next++
+	if l.next == len(l.buf) {
+		l.next = 0
+		l.full = true
+	}
+}
+
+// Keeps the n most recent mutations (writes, removals with their reason, soft deletes and
+// resurrections) in memory, exposed through RecentEvents, as a flight recorder for investigating why
+// a key disappeared without external logging. Overwrites are recorded as a single OpSet.
+func WithEventLog[K comparable, V any](n int) Option[K, V] {
+	if n <= 0 {
+		panic("event log size must be greater than zero")
+	}
+	return func(c *Cache[K, V]) {
+		c.events = &eventLog[K]{buf: make([]Event[K], n)}
+	}
+}
+
+// Returns the recorded events for which filter returns true, oldest first; a nil filter keeps all events.
+// It returns nil if the event log is not enabled with WithEventLog.
+func (c *Cache[K, V]) RecentEvents(filter func(Event[K]) bool) []Event[K] {
+	c.lock()
+	defer c.unlock()
+
+	if c.events == nil {
+		return nil
+	}
+	l := c.events
+	var out []Event[K]
+	keep := func(events []Event[K]) {
+		for _, e := range events {
+			if filter == nil || filter(e) {
+				out = append(out, e)
+			}
+		}
+	}
+	if l.full {
+		keep(l.buf[l.next:])
+	}
+	keep(l.buf[:l.next])
+	return out
+}
+
+// Records a mutation in the event log, if enabled, and queues it for the subscribers and the watchers of key.
+func (c *Cache[K, V]) logEventLocked(op EventOp, key K, value V, reason EvictionReason) {
+	if c.events == nil && len(c.subscribers) == 0 && len(c.watchers) == 0 {
+		return
+	}
+	e := Event[K]{Op: op, Key: key, Reason: reason, Time: c.clock.Now()}
+	if c.events != nil {
+		c.events.record(e)
+	}
+	for _, s := range c.subscribers {
+		if s.publish(e, value) {
+			c.droppedEvents++
+		}
+	}
+	for _, s := range c.watchers[key] {
+		if s.publish(e, value) {
+			c.droppedEvents++
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package goutte_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheRecentEvents(t *testing.T) {
+	cache := goutte.NewCache[string, int](2, goutte.WithEventLog[string, int](4))
+	defer cache.Close()
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3) // evicts a
+	cache.SoftDelete("b", time.Minute)
+	cache.Resurrect("b")
+	cache.Delete("c")
+
+	// The log only keeps the last 4 events.
+	events := cache.RecentEvents(nil)
+	expected := []goutte.Event[string]{
+		{Op: goutte.OpRemove, Key: "a", Reason: goutte.EvictionCapacity},
+		{Op: goutte.OpSoftDelete, Key: "b"},
+		{Op: goutte.OpResurrect, Key: "b"},
+		{Op: goutte.OpRemove, Key: "c", Reason: goutte.EvictionDeleted},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %+v", len(expected), events)
+	}
+	for i, e := range events {
+		if e.Op != expected[i].Op || e.Key != expected[i].Key || e.Reason != expected[i].Reason || e.Time.IsZero() {
+			t.Errorf("Event %d: expected %v %q (%v), got %v %q (%v)", i, expected[i].Op, expected[i].Key, expected[i].Reason, e.Op, e.Key, e.Reason)
+		}
+	}
+
+	onlyB := cache.RecentEvents(func(e goutte.Event[string]) bool { return e.Key == "b" })
+	if len(onlyB) != 2 || onlyB[0].Op != goutte.OpSoftDelete || onlyB[1].Op != goutte.OpResurrect {
+		t.Errorf("Expected the soft delete and resurrection of 'b', got %+v", onlyB)
+	}
+
+	cache.Dump()
+	if events := cache.RecentEvents(nil); events[3].Reason != goutte.EvictionCleared {
+		t.Errorf("Expected Dump to be recorded as cleared, got %+v", events[3])
+	}
+}
@@ -0,0 +1,136 @@
+package goutte
+
+import "time"
+
+// Sets the same absolute expiration on several existing keys under a single lock acquisition, so the
+// entries of a composite object expire together: lookups compare them against the same deadline, and
+// the expiration goroutine removes all of them in one pass, whatever JanitorConfig.BatchSize is.
+// Missing, expired and soft-deleted keys are skipped. A group deadline overrides sliding TTLs and any
+// hit streak TTL policy for the updated entries. It returns the number of entries updated.
+func (c *Cache[K, V]) SetGroupDeadline(keys []K, at time.Time) int {
+	c.lock()
+	defer c.unlock()
+
+	updated := 0
+	for _, key := range keys {
+		if ele := c.lookupLocked(key); ele != nil {
+			ent := ele.Value.(*entry[K, V])
+			ent.streak = nil
+			ent.ttl = 0
+			ent.sliding = false
+			c.setExpirationLocked(ent, at)
+			if c.migration != nil {
+				c.mirrorLocked(key)
+			}
+			updated++
+		}
+	}
+	return updated
+}
+
+// Resets the expiration of an existing entry to the TTL it was last written with, counted from now,
+// without rewriting or promoting it, e.g. to keep a session alive. It reports whether a live entry was
+// found. Entries without a TTL, or whose TTL is not known (such as those migrated from another cache
+// or given a group deadline), are left unchanged.
+func (c *Cache[K, V]) Touch(key K) bool {
+	c.lock()
+	defer c.unlock()
+
+	ele := c.lookupLocked(key)
+	if ele == nil {
+		return false
+	}
+	if ent := ele.Value.(*entry[K, V]); ent.ttl > 0 {
+		c.setExpirationLocked(ent, c.clock.Now().Add(ent.ttl))
+		if c.migration != nil {
+			c.mirrorLocked(key)
+		}
+	}
+	return true
+}
+
+// Extends the expiration of an existing entry so that at least ttl remains, without rewriting or
+// promoting it. An expiration already further away is kept, and entries without a TTL are left
+// unchanged. It reports whether a live entry was found.
+func (c *Cache[K, V]) TouchWithTTL(key K, ttl time.Duration) bool {
+	c.lock()
+	defer c.unlock()
+
+	ele := c.lookupLocked(key)
+	if ele == nil {
+		return false
+	}
+	ent := ele.Value.(*entry[K, V])
+	if extended := c.clock.Now().Add(ttl); !ent.expiration.IsZero() && extended.After(ent.expiration) {
+		c.setExpirationLocked(ent, extended)
+		if c.migration != nil {
+			c.mirrorLocked(key)
+		}
+	}
+	return true
+}
+
+// Attaches or replaces the TTL of an existing entry, counted from now, without rewriting or promoting
+// it, like the Redis EXPIRE command. A non-positive ttl removes the entry as expired. It reports whether
+// a live entry was found.
+func (c *Cache[K, V]) Expire(key K, ttl time.Duration) bool {
+	c.lock()
+	defer c.unlock()
+
+	ele := c.lookupLocked(key)
+	if ele == nil {
+		return false
+	}
+	if ttl <= 0 {
+		c.removeElementLocked(ele, EvictionExpired)
+		if c.migration != nil {
+			c.mirrorLocked(key)
+		}
+		return true
+	}
+	ent := ele.Value.(*entry[K, V])
+	ent.ttl = ttl
+	ent.sliding = c.expireAfterAccess
+	ent.streak = nil
+	c.setExpirationLocked(ent, c.clock.Now().Add(ttl))
+	if c.migration != nil {
+		c.mirrorLocked(key)
+	}
+	return true
+}
+
+// Removes the TTL of an existing entry so it never expires, like the Redis PERSIST command.
+// It reports whether a TTL was removed.
+func (c *Cache[K, V]) Persist(key K) bool {
+	c.lock()
+	defer c.unlock()
+
+	ele := c.lookupLocked(key)
+	if ele == nil {
+		return false
+	}
+	ent := ele.Value.(*entry[K, V])
+	if ent.expiration.IsZero() {
+		return false
+	}
+	ent.ttl = 0
+	ent.sliding = false
+	ent.streak = nil
+	c.setExpirationLocked(ent, time.Time{})
+	if c.migration != nil {
+		c.mirrorLocked(key)
+	}
+	return true
+}
+
+// Removes every expired entry now rather than waiting for the expiration goroutine, e.g. during a
+// maintenance window, and returns the number of entries removed. Removals are reported to the
+// callbacks and counted in Stats as expirations.
+func (c *Cache[K, V]) DeleteExpired() int {
+	c.lock()
+	defer c.unlock()
+
+	n := c.expireDueLocked(c.clock.Now(), 0)
+	c.janitor.removed += uint64(n)
+	return n
+}
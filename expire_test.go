@@ -0,0 +1,205 @@
+package goutte_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheSetGroupDeadline(t *testing.T) {
+	cache := goutte.NewCache[string, int](4)
+	defer cache.Close()
+	cache.Set("order:1", 1)
+	cache.SetWithTTL("order:1:items", 2, time.Hour)
+	cache.Set("other", 3)
+
+	at := time.Now().Add(50 * time.Millisecond)
+	if n := cache.SetGroupDeadline([]string{"order:1", "order:1:items", "missing"}, at); n != 2 {
+		t.Errorf("Expected 2 entries to join the group, got %d", n)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if _, ok := cache.Get("order:1"); ok {
+		t.Error("Expected key 'order:1' to have expired with its group")
+	}
+	if _, ok := cache.Get("order:1:items"); ok {
+		t.Error("Expected key 'order:1:items' to have expired with its group")
+	}
+	if _, ok := cache.Get("other"); !ok {
+		t.Error("Expected key 'other' to be unaffected")
+	}
+}
+
+func TestCacheSlidingTTL(t *testing.T) {
+	cache := goutte.NewCache[string, int](4)
+	defer cache.Close()
+
+	cache.SetWithSlidingTTL("session", 1, 60*time.Millisecond)
+	cache.SetWithTTL("fixed", 2, 60*time.Millisecond)
+
+	// Reading every 30ms keeps the sliding entry alive past its TTL.
+	for i := 0; i < 4; i++ {
+		time.Sleep(30 * time.Millisecond)
+		cache.Get("session")
+		cache.Get("fixed")
+	}
+	if !cache.Contains("session") {
+		t.Error("Expected the sliding entry to be kept alive by reads")
+	}
+	if cache.Contains("fixed") {
+		t.Error("Expected the fixed entry to expire despite reads")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if cache.Contains("session") {
+		t.Error("Expected the sliding entry to expire once idle")
+	}
+}
+
+func TestCacheExpireAfterAccess(t *testing.T) {
+	cache := goutte.NewCache[string, int](4,
+		goutte.WithDefaultTTL[string, int](60*time.Millisecond),
+		goutte.WithExpireAfterAccess[string, int]())
+	defer cache.Close()
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	for i := 0; i < 4; i++ {
+		time.Sleep(30 * time.Millisecond)
+		cache.Get("a")
+	}
+	if !cache.Contains("a") || cache.Contains("b") {
+		t.Error("Expected only the entry being read to survive")
+	}
+}
+
+func TestCacheTouch(t *testing.T) {
+	cache := goutte.NewCache[string, int](4)
+	defer cache.Close()
+
+	cache.SetWithTTL("session", 1, 60*time.Millisecond)
+	cache.Set("forever", 2)
+	for i := 0; i < 4; i++ {
+		time.Sleep(30 * time.Millisecond)
+		if !cache.Touch("session") {
+			t.Fatal("Expected Touch to find 'session'")
+		}
+	}
+	if !cache.Contains("session") {
+		t.Error("Expected Touch to keep 'session' alive")
+	}
+	if cache.Touch("missing") {
+		t.Error("Expected Touch to report a missing key")
+	}
+
+	// TouchWithTTL only extends.
+	cache.TouchWithTTL("session", time.Minute)
+	cache.TouchWithTTL("session", time.Millisecond)
+	cache.TouchWithTTL("forever", time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	if !cache.Contains("session") || !cache.Contains("forever") {
+		t.Error("Expected TouchWithTTL never to shorten an expiration")
+	}
+}
+
+func TestCacheExpirePersist(t *testing.T) {
+	cache := goutte.NewCache[string, int](4)
+	defer cache.Close()
+
+	cache.Set("a", 1)
+	cache.SetWithTTL("b", 2, 20*time.Millisecond)
+	cache.Set("c", 3)
+
+	if !cache.Expire("a", 20*time.Millisecond) {
+		t.Error("Expected Expire to find 'a'")
+	}
+	if !cache.Persist("b") {
+		t.Error("Expected Persist to remove the TTL of 'b'")
+	}
+	if cache.Persist("c") {
+		t.Error("Expected Persist to report that 'c' had no TTL")
+	}
+	if !cache.Expire("c", 0) || cache.Contains("c") {
+		t.Error("Expected a non-positive Expire to remove 'c'")
+	}
+	if cache.Expire("missing", time.Minute) || cache.Persist("missing") {
+		t.Error("Expected Expire and Persist to report a missing key")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if cache.Contains("a") {
+		t.Error("Expected 'a' to expire with its new TTL")
+	}
+	if val, ok := cache.Get("b"); !ok || val != 2 {
+		t.Errorf("Expected 'b' to be persisted, got %d (found: %v)", val, ok)
+	}
+}
+
+func TestCacheDeleteExpired(t *testing.T) {
+	cache := goutte.NewCache[string, int](4)
+	defer cache.Close()
+	cache.SetWithTTL("a", 1, 20*time.Millisecond)
+	cache.SetWithTTL("b", 2, 20*time.Millisecond)
+	cache.SetWithTTL("c", 3, time.Hour)
+	cache.Set("d", 4)
+
+	if n := cache.DeleteExpired(); n != 0 {
+		t.Errorf("Expected no entries to be removed before the TTL elapses, got %d", n)
+	}
+	time.Sleep(50 * time.Millisecond)
+	// The expiration goroutine may have removed some of them already.
+	n := cache.DeleteExpired()
+	if stats := cache.Stats(); stats.Expirations != 2 || n > 2 {
+		t.Errorf("Expected 2 expirations, got %d (removed by DeleteExpired: %d)", stats.Expirations, n)
+	}
+	if n := cache.DeleteExpired(); n != 0 {
+		t.Errorf("Expected nothing left to remove, got %d", n)
+	}
+	if n := cache.Len(); n != 2 {
+		t.Errorf("Expected 2 entries left, got %d", n)
+	}
+}
+
+func TestCacheSetGroupDeadlineSinglePass(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []goutte.Option[int, int]
+	}{
+		{"heap", nil},
+		{"wheel", []goutte.Option[int, int]{goutte.WithTimingWheel[int, int](time.Second)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := goutte.NewManualClock(time.Unix(0, 0))
+			expired := make(chan int, 8)
+			opts := append(tc.opts,
+				goutte.WithClock[int, int](clock),
+				goutte.WithJanitor[int, int](goutte.JanitorConfig{BatchSize: 1}),
+				goutte.WithOnRemoval(func(key, _ int, reason goutte.EvictionReason) {
+					if reason == goutte.EvictionExpired {
+						expired <- key
+					}
+				}))
+			cache := goutte.NewCache(8, opts...)
+			defer cache.Close()
+
+			keys := []int{1, 2, 3, 4, 5}
+			for _, key := range keys {
+				cache.Set(key, key)
+			}
+			cache.SetGroupDeadline(keys, clock.Now().Add(time.Minute))
+			clock.Advance(2 * time.Minute)
+
+			for range keys {
+				select {
+				case <-expired:
+				case <-time.After(time.Second):
+					t.Fatal("Timed out waiting for the group to expire")
+				}
+			}
+			if passes := cache.JanitorStats().Passes; passes != 1 {
+				t.Errorf("Expected the group to be removed in 1 pass despite a batch size of 1, got %d passes", passes)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package goutte
+
+import "maps"
+
+// Sets a single field of a map-valued entry under the cache lock.
+// If the key is missing (or expired), a new map holding only that field is inserted with the default TTL, if any.
+// The entry is moved to the front of the list, like Set. The stored map is replaced by an updated
+// copy, so maps returned by Get and other reads are never modified.
+func SetField[K comparable, F comparable, V any](c *Cache[K, map[F]V], key K, field F, value V) {
+	c.lock()
+	defer c.unlock()
+
+	if ele := c.lookupLocked(key); ele != nil {
+		m := maps.Clone(ele.Value.(*entry[K, map[F]V]).value)
+		if m == nil {
+			m = make(map[F]V)
+		}
+		m[field] = value
+		c.promoteLocked(ele)
+		c.updateLocked(ele, m)
+		return
+	}
+
+	c.writeLocked(key, map[F]V{field: value}, c.defaultTTL)
+}
+
+// Retrieves a single field of a map-valued entry under the cache lock.
+// The second result is false if either the key or the field is missing.
+func GetField[K comparable, F comparable, V any](c *Cache[K, map[F]V], key K, field F) (V, bool) {
+	c.lock()
+	defer c.unlock()
+
+	if ele := c.lookupLocked(key); ele != nil {
+		c.hitLocked(ele)
+		v, ok := ele.Value.(*entry[K, map[F]V]).value[field]
+		return v, ok
+	}
+
+	c.missLocked(key)
+	var zero V
+	return zero, false
+}
+
+// Removes a single field of a map-valued entry under the cache lock.
+// It reports whether the field was present. The entry itself is kept even if its map becomes empty.
+// Like SetField, it stores an updated copy of the map.
+func DeleteField[K comparable, F comparable, V any](c *Cache[K, map[F]V], key K, field F) bool {
+	c.lock()
+	defer c.unlock()
+
+	ele := c.lookupLocked(key)
+	if ele == nil {
+		return false
+	}
+	m := ele.Value.(*entry[K, map[F]V]).value
+	if _, ok := m[field]; !ok {
+		return false
+	}
+	m = maps.Clone(m)
+	delete(m, field)
+	c.updateLocked(ele, m)
+	return true
+}
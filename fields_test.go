@@ -0,0 +1,170 @@
+package goutte_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheFields(t *testing.T) {
+	cache := goutte.NewCache[string, map[string]int](2)
+	defer cache.Close()
+
+	// Setting a field on a missing key creates the map.
+	goutte.SetField(cache, "user", "age", 30)
+	goutte.SetField(cache, "user", "score", 7)
+
+	if val, ok := goutte.GetField(cache, "user", "age"); !ok || val != 30 {
+		t.Errorf("Expected field 'age' to have value 30, got %v (found: %v)", val, ok)
+	}
+	if _, ok := goutte.GetField(cache, "user", "missing"); ok {
+		t.Error("Expected field 'missing' to be absent")
+	}
+
+	if !goutte.DeleteField(cache, "user", "age") {
+		t.Error("Expected DeleteField to report the field 'age' as removed")
+	}
+	if goutte.DeleteField(cache, "user", "age") {
+		t.Error("Expected a second DeleteField of 'age' to report nothing removed")
+	}
+
+	// The entry survives with its remaining field.
+	if m, ok := cache.Get("user"); !ok || len(m) != 1 || m["score"] != 7 {
+		t.Errorf("Expected map with only 'score', got %v (found: %v)", m, ok)
+	}
+}
+
+func TestCacheFieldsConcurrency(t *testing.T) {
+	cache := goutte.NewCache[string, map[int]int](1)
+	defer cache.Close()
+	var wg sync.WaitGroup
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			goutte.SetField(cache, "counters", i, i*10)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < 100; i++ {
+		if val, ok := goutte.GetField(cache, "counters", i); !ok || val != i*10 {
+			t.Errorf("For field %d, expected %d but got %d (found: %v)", i, i*10, val, ok)
+		}
+	}
+}
+
+func TestCacheFieldsCopyOnWrite(t *testing.T) {
+	cache := goutte.NewCache(2, goutte.WithWeigher(100, func(_ string, m map[string]int) int64 {
+		return int64(len(m))
+	}))
+	defer cache.Close()
+	goutte.SetField(cache, "user", "age", 30)
+
+	// A map returned by Get is a snapshot that later field updates do not touch.
+	snapshot, _ := cache.Get("user")
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 100 {
+			goutte.SetField(cache, "user", "score", i)
+		}
+	}()
+	for range 100 {
+		_ = snapshot["score"]
+	}
+	wg.Wait()
+	if len(snapshot) != 1 {
+		t.Errorf("Expected the map returned by Get to be unchanged, got %v", snapshot)
+	}
+
+	// Field updates re-weigh the entry.
+	if w := cache.Stats().Weight; w != 2 {
+		t.Errorf("Expected weight 2 after adding a field, got %d", w)
+	}
+	goutte.DeleteField(cache, "user", "age")
+	if w := cache.Stats().Weight; w != 1 {
+		t.Errorf("Expected weight 1 after deleting a field, got %d", w)
+	}
+}
+
+func TestCacheFieldsEvents(t *testing.T) {
+	cache := goutte.NewCache(2, goutte.WithEventLog[string, map[string]int](8))
+	defer cache.Close()
+
+	goutte.SetField(cache, "user", "age", 30)
+	goutte.SetField(cache, "user", "score", 7)
+	goutte.DeleteField(cache, "user", "age")
+	goutte.DeleteField(cache, "user", "missing")
+
+	events := cache.RecentEvents(nil)
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %+v", events)
+	}
+	for _, e := range events {
+		if e.Op != goutte.OpSet || e.Key != "user" {
+			t.Errorf("Expected a set of key 'user', got %+v", e)
+		}
+	}
+}
+
+func TestCacheFieldsNotifyReplaced(t *testing.T) {
+	var replaced []map[string]int
+	cache := goutte.NewCache(2, goutte.WithOnRemoval(func(_ string, m map[string]int, reason goutte.EvictionReason) {
+		if reason == goutte.EvictionReplaced {
+			replaced = append(replaced, m)
+		}
+	}))
+	defer cache.Close()
+
+	goutte.SetField(cache, "a", "x", 1)
+	goutte.SetField(cache, "a", "y", 2)
+	goutte.DeleteField(cache, "a", "x")
+	if len(replaced) != 2 || len(replaced[0]) != 1 || len(replaced[1]) != 2 {
+		t.Errorf("Expected the previous maps to be reported as replaced, got %v", replaced)
+	}
+}
+
+func TestCacheFieldsInvalidateRefresh(t *testing.T) {
+	clock := goutte.NewManualClock(time.Unix(0, 0))
+	started := make(chan struct{})
+	release := make(chan struct{})
+	returned := make(chan struct{})
+	loads := 0
+	loader := func(ctx context.Context, key string) (map[string]int, error) {
+		loads++
+		if loads == 1 {
+			return map[string]int{"x": 1}, nil
+		}
+		close(started)
+		<-release
+		defer close(returned)
+		return map[string]int{"x": 2}, nil
+	}
+	cache := goutte.NewLoadingCache(goutte.NewCache(2, goutte.WithClock[string, map[string]int](clock)), loader,
+		goutte.WithRefreshAfter[string, map[string]int](time.Second))
+	defer cache.Close()
+
+	ctx := context.Background()
+	cache.Get(ctx, "a")
+	clock.Advance(2 * time.Second)
+	cache.Get(ctx, "a") // starts a refresh
+	<-started
+	goutte.SetField(cache.Cache, "a", "y", 3)
+	close(release)
+	<-returned
+
+	// The refresh stores its result right after the loader returns, unless it went stale.
+	deadline := time.Now().Add(50 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		if _, ok := goutte.GetField(cache.Cache, "a", "y"); !ok {
+			t.Fatal("Expected the refresh not to overwrite the newer field update")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,156 @@
+package goutte
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// LRU cache for keys that are not comparable, such as slices, maps or structs holding them, which
+// are identified by user-supplied hash and equality functions instead of being serialized to strings.
+// Keys are indexed by hash and told apart with equal; each key has its own entry, with its own
+// recency, expiration and slot of the capacity, even when hashes collide.
+type HashedCache[K, V any] struct {
+	cache *Cache[*hashedKey[K], V]
+	hash  func(K) uint64
+	equal func(a, b K) bool
+
+	mu    sync.Mutex                 // guards index; held across writes to cache
+	index map[uint64][]*hashedKey[K] // keys by hash, including some removed from cache since the last write
+
+	removedMu sync.Mutex
+	removed   []*hashedKey[K] // keys removed from cache, to drop from index on the next write
+}
+
+// Key stored in the underlying cache, which compares keys by the identity of this box.
+type hashedKey[K any] struct {
+	key  K
+	hash uint64
+}
+
+// Creates a cache with a given capacity for keys identified by hash and equal. Keys that are equal
+// must have the same hash, and neither function may be nil. Keys must not be modified once stored.
+func NewHashedCache[K, V any](capacity int, hash func(K) uint64, equal func(a, b K) bool) *HashedCache[K, V] {
+	if hash == nil || equal == nil {
+		panic("hash and equal functions must not be nil")
+	}
+	c := &HashedCache[K, V]{
+		hash:  hash,
+		equal: equal,
+		index: make(map[uint64][]*hashedKey[K]),
+	}
+	// The callback runs after the cache lock is released but possibly while c.mu is held by a write,
+	// so it only queues the key.
+	c.cache = NewCache(capacity, WithOnRemoval(func(hk *hashedKey[K], _ V, reason EvictionReason) {
+		if reason == EvictionReplaced {
+			return
+		}
+		c.removedMu.Lock()
+		c.removed = append(c.removed, hk)
+		c.removedMu.Unlock()
+	}))
+	return c
+}
+
+// Retrieves the value associated with the given key, promoting its entry.
+func (c *HashedCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	hk := c.findLocked(c.hash(key), key)
+	c.mu.Unlock()
+
+	if hk == nil {
+		var zero V
+		return zero, false
+	}
+	// A key removed meanwhile is simply missing from cache.
+	return c.cache.Get(hk)
+}
+
+// Inserts or updates a key-value pair in the cache.
+func (c *HashedCache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, 0)
+}
+
+// Inserts or updates a key-value pair in the cache with an optional TTL; see Cache.SetWithTTL.
+func (c *HashedCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	h := c.hash(key)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pruneLocked()
+	hk := c.findLocked(h, key)
+	if hk == nil {
+		hk = &hashedKey[K]{key: key, hash: h}
+		c.index[h] = append(c.index[h], hk)
+	}
+	c.cache.SetWithTTL(hk, value, ttl)
+}
+
+// Removes a key from the cache if it exists.
+func (c *HashedCache[K, V]) Delete(key K) {
+	h := c.hash(key)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pruneLocked()
+	if hk := c.findLocked(h, key); hk != nil {
+		c.cache.Delete(hk)
+		c.unindexLocked(hk)
+	}
+}
+
+// Returns the indexed key equal to key, or nil.
+func (c *HashedCache[K, V]) findLocked(h uint64, key K) *hashedKey[K] {
+	for _, hk := range c.index[h] {
+		if c.equal(hk.key, key) {
+			return hk
+		}
+	}
+	return nil
+}
+
+// Drops the keys removed from cache, by eviction or expiration, from the index. A key written again
+// since its removal is kept: writes hold c.mu, so it is either back in cache or not written yet.
+func (c *HashedCache[K, V]) pruneLocked() {
+	c.removedMu.Lock()
+	removed := c.removed
+	c.removed = nil
+	c.removedMu.Unlock()
+
+	for _, hk := range removed {
+		if !c.cache.Contains(hk) {
+			c.unindexLocked(hk)
+		}
+	}
+}
+
+func (c *HashedCache[K, V]) unindexLocked(hk *hashedKey[K]) {
+	keys := c.index[hk.hash]
+	i := slices.Index(keys, hk)
+	switch {
+	case i < 0:
+	case len(keys) == 1:
+		delete(c.index, hk.hash)
+	default:
+		c.index[hk.hash] = slices.Delete(keys, i, i+1)
+	}
+}
+
+// Returns the number of entries in the cache.
+func (c *HashedCache[K, V]) Len() int {
+	return c.cache.Len()
+}
+
+// Clears all entries from the cache.
+func (c *HashedCache[K, V]) Dump() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cache.Dump()
+	clear(c.index)
+}
+
+// Stops the background expiration goroutine.
+func (c *HashedCache[K, V]) Close() {
+	c.cache.Close()
+}
@@ -0,0 +1,94 @@
+package goutte_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+// Hashes every slice to the same value, so that all keys collide.
+func constHash([]int) uint64 {
+	return 42
+}
+
+func TestHashedCache(t *testing.T) {
+	cache := goutte.NewHashedCache[[]int, string](2, constHash, slices.Equal[[]int])
+	defer cache.Close()
+
+	cache.Set([]int{1, 2}, "a")
+	cache.Set([]int{3, 4}, "b")
+	cache.Set([]int{1, 2}, "c")
+
+	if val, ok := cache.Get([]int{1, 2}); !ok || val != "c" {
+		t.Errorf("Expected 'c', got %q (found: %v)", val, ok)
+	}
+	if val, ok := cache.Get([]int{3, 4}); !ok || val != "b" {
+		t.Errorf("Expected 'b', got %q (found: %v)", val, ok)
+	}
+	if _, ok := cache.Get([]int{5, 6}); ok {
+		t.Error("Expected a colliding key that was never set to be missing")
+	}
+	if n := cache.Len(); n != 2 {
+		t.Errorf("Expected colliding keys to count as 2 entries, got %d", n)
+	}
+
+	cache.Delete([]int{1, 2})
+	if _, ok := cache.Get([]int{1, 2}); ok {
+		t.Error("Expected [1 2] to be deleted")
+	}
+	if val, ok := cache.Get([]int{3, 4}); !ok || val != "b" {
+		t.Errorf("Expected [3 4] to survive the deletion of a colliding key, got %q (found: %v)", val, ok)
+	}
+}
+
+func TestHashedCacheCollisionsEvictSeparately(t *testing.T) {
+	cache := goutte.NewHashedCache[[]int, int](2, constHash, slices.Equal[[]int])
+	defer cache.Close()
+
+	cache.Set([]int{1}, 1)
+	cache.Set([]int{2}, 2)
+	cache.Get([]int{1})
+	cache.Set([]int{3}, 3)
+
+	if _, ok := cache.Get([]int{2}); ok {
+		t.Error("Expected the least recently used key to be evicted")
+	}
+	for _, key := range [][]int{{1}, {3}} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("Expected %v to be present", key)
+		}
+	}
+
+	// An evicted key can be written again.
+	cache.Set([]int{2}, 20)
+	if val, ok := cache.Get([]int{2}); !ok || val != 20 {
+		t.Errorf("Expected 20, got %v (found: %v)", val, ok)
+	}
+	if n := cache.Len(); n != 2 {
+		t.Errorf("Expected 2 entries, got %d", n)
+	}
+}
+
+func TestHashedCacheCollisionsExpireSeparately(t *testing.T) {
+	cache := goutte.NewHashedCache[[]int, int](4, constHash, slices.Equal[[]int])
+	defer cache.Close()
+
+	cache.SetWithTTL([]int{1}, 1, 30*time.Millisecond)
+	cache.Set([]int{2}, 2)
+	time.Sleep(10 * time.Millisecond)
+	// Writing a colliding key leaves the expiration of the others alone.
+	cache.Set([]int{2}, 20)
+	cache.SetWithTTL([]int{3}, 3, time.Hour)
+	time.Sleep(40 * time.Millisecond)
+
+	if _, ok := cache.Get([]int{1}); ok {
+		t.Error("Expected [1] to have expired")
+	}
+	for _, key := range [][]int{{2}, {3}} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("Expected %v to be present", key)
+		}
+	}
+}
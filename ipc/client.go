@@ -0,0 +1,90 @@
+package ipc
+
+import (
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+)
+
+// Connection to a Server. Safe for concurrent use; requests are serialized on a single connection.
+type Client struct {
+	mu   sync.Mutex // serializes request/response round trips
+	conn net.Conn
+}
+
+// Connects to a server listening on the Unix domain socket at path.
+func Dial(path string) (*Client, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn), nil
+}
+
+// Wraps an already established connection, e.g. one end of a socketpair inherited by a child process.
+func NewClient(conn net.Conn) *Client {
+	return &Client{conn: conn}
+}
+
+// Retrieves the value for key from the remote cache.
+func (c *Client) Get(key string) ([]byte, bool, error) {
+	req := appendBytes([]byte{opGet}, []byte(key))
+	resp, err := c.roundTrip(req)
+	if err != nil {
+		return nil, false, err
+	}
+	switch resp[0] {
+	case statusNotFound:
+		return nil, false, nil
+	case statusOK:
+		val, _, err := consumeBytes(resp[1:])
+		if err != nil {
+			return nil, false, err
+		}
+		return val, true, nil
+	}
+	return nil, false, ErrMalformed
+}
+
+// Inserts or updates a key-value pair in the remote cache with an optional TTL.
+func (c *Client) Set(key string, value []byte, ttl time.Duration) error {
+	req := appendBytes([]byte{opSet}, []byte(key))
+	req = binary.BigEndian.AppendUint64(req, uint64(ttl))
+	req = appendBytes(req, value)
+	_, err := c.roundTrip(req)
+	return err
+}
+
+// Removes a key from the remote cache if it exists.
+func (c *Client) Delete(key string) error {
+	req := appendBytes([]byte{opDelete}, []byte(key))
+	_, err := c.roundTrip(req)
+	return err
+}
+
+// Closes the underlying connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Sends a request and returns the response payload, translating error statuses into a RemoteError.
+func (c *Client) roundTrip(req []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := writeFrame(c.conn, req); err != nil {
+		return nil, err
+	}
+	resp, err := readFrame(c.conn)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp) == 0 {
+		return nil, ErrMalformed
+	}
+	if resp[0] == statusError {
+		return nil, &RemoteError{Message: string(resp[1:])}
+	}
+	return resp, nil
+}
@@ -0,0 +1,35 @@
+// Package ipc exposes a goutte cache to other local processes over a stream socket, typically a
+// Unix domain socket, so short-lived child processes can reuse the warm cache of a parent daemon.
+//
+// The protocol is a minimal length-prefixed binary format. Every message is framed as a 4-byte
+// big-endian payload length followed by the payload. A request payload starts with an opcode byte:
+//
+//	Get:      0x01 | key
+//	Set:      0x02 | key | ttl (int64 nanoseconds, big-endian) | value
+//	Delete:   0x03 | key
+//	Snapshot: 0x04 | empty key | rate (int64 entries per second, 0 for unlimited)
+//
+// where key and value are each encoded as a 4-byte big-endian length followed by the raw bytes.
+// A response payload starts with a status byte (0x00 ok, 0x01 not found, 0x02 error), followed by
+// the value for a successful Get or the error message for a failed request.
+//
+// A Snapshot request is answered with a stream of entry frames, from least to most recently used,
+// each encoded as 0x00 | key | expiration (int64 Unix nanoseconds, 0 for none) | value, and terminated
+// by a single 0x03 frame. WarmFromPeer uses it to start a new process with the contents of a running one.
+//
+// ## Usage Example
+//
+//	// In the daemon:
+//	cache := goutte.NewCache[string, []byte](10000)
+//	l, _ := net.Listen("unix", "/run/myapp/cache.sock")
+//	srv := ipc.NewServer(cache)
+//	go srv.Serve(l)
+//	defer srv.Close()
+//
+//	// In a child process:
+//	client, _ := ipc.Dial("/run/myapp/cache.sock")
+//	defer client.Close()
+//	if val, found, err := client.Get("config"); err == nil && found {
+//		fmt.Println(string(val))
+//	}
+package ipc
@@ -0,0 +1,115 @@
+package ipc_test
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+	"github.com/shellkah/goutte/ipc"
+)
+
+// Starts a server on a fresh Unix socket and returns it along with the socket path.
+func listen(t *testing.T, cache *goutte.Cache[string, []byte]) (*ipc.Server, string) {
+	t.Helper()
+
+	// Socket paths are limited in length, so avoid the long t.TempDir paths.
+	dir, err := os.MkdirTemp("", "goutte")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "cache.sock")
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := ipc.NewServer(cache)
+	go srv.Serve(l)
+	t.Cleanup(func() { srv.Close() })
+	return srv, path
+}
+
+// Starts a server on a fresh Unix socket and returns a connected client.
+func startServer(t *testing.T, cache *goutte.Cache[string, []byte]) *ipc.Client {
+	t.Helper()
+
+	_, path := listen(t, cache)
+	client, err := ipc.Dial(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestIPCGetSetDelete(t *testing.T) {
+	cache := goutte.NewCache[string, []byte](10)
+	defer cache.Close()
+	client := startServer(t, cache)
+
+	if _, found, err := client.Get("a"); err != nil || found {
+		t.Fatalf("Expected key 'a' to be missing, got found=%v err=%v", found, err)
+	}
+
+	if err := client.Set("a", []byte("hello"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if val, found, err := client.Get("a"); err != nil || !found || string(val) != "hello" {
+		t.Errorf("Expected key 'a' to have value 'hello', got %q (found: %v, err: %v)", val, found, err)
+	}
+
+	// Writes through the socket are visible to the parent process.
+	if val, ok := cache.Get("a"); !ok || string(val) != "hello" {
+		t.Errorf("Expected local key 'a' to have value 'hello', got %q (found: %v)", val, ok)
+	}
+
+	if err := client.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected key 'a' to be deleted")
+	}
+}
+
+func TestIPCSetWithTTL(t *testing.T) {
+	cache := goutte.NewCache[string, []byte](10)
+	defer cache.Close()
+	client := startServer(t, cache)
+
+	if err := client.Set("a", []byte("1"), 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	if _, found, err := client.Get("a"); err != nil || found {
+		t.Errorf("Expected key 'a' to have expired, got found=%v err=%v", found, err)
+	}
+}
+
+func TestIPCConcurrentClients(t *testing.T) {
+	cache := goutte.NewCache[string, []byte](1000)
+	defer cache.Close()
+	client := startServer(t, cache)
+	var wg sync.WaitGroup
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := string(rune('a' + i%26))
+			if err := client.Set(key, []byte(key), 0); err != nil {
+				t.Error(err)
+				return
+			}
+			if val, found, err := client.Get(key); err != nil || !found || string(val) != key {
+				t.Errorf("For key %q, expected same value but got %q (found: %v, err: %v)", key, val, found, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
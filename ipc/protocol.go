@@ -0,0 +1,98 @@
+package ipc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Request opcodes.
+const (
+	opGet      byte = 0x01
+	opSet      byte = 0x02
+	opDelete   byte = 0x03
+	opSnapshot byte = 0x04
+)
+
+// Response status codes.
+const (
+	statusOK       byte = 0x00
+	statusNotFound byte = 0x01
+	statusError    byte = 0x02
+	statusEnd      byte = 0x03 // terminates a snapshot stream
+)
+
+// Largest payload accepted on either side of a connection.
+const MaxFrameSize = 64 << 20
+
+var (
+	// Returned when a peer announces a payload larger than MaxFrameSize.
+	ErrFrameTooLarge = errors.New("ipc: frame exceeds maximum size")
+	// Returned when a payload cannot be decoded.
+	ErrMalformed = errors.New("ipc: malformed message")
+)
+
+// Reads one length-prefixed payload.
+func readFrame(r io.Reader) ([]byte, error) {
+	var hdr [4]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(hdr[:])
+	if n > MaxFrameSize {
+		return nil, ErrFrameTooLarge
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// Writes one length-prefixed payload.
+func writeFrame(w io.Writer, payload []byte) error {
+	if len(payload) > MaxFrameSize {
+		return ErrFrameTooLarge
+	}
+	buf := make([]byte, 4, 4+len(payload))
+	binary.BigEndian.PutUint32(buf, uint32(len(payload)))
+	_, err := w.Write(append(buf, payload...))
+	return err
+}
+
+// Appends a 4-byte length followed by b.
+func appendBytes(dst, b []byte) []byte {
+	dst = binary.BigEndian.AppendUint32(dst, uint32(len(b)))
+	return append(dst, b...)
+}
+
+// Decodes a length-prefixed field from the head of b and returns it along with the remainder.
+func consumeBytes(b []byte) ([]byte, []byte, error) {
+	if len(b) < 4 {
+		return nil, nil, ErrMalformed
+	}
+	n := binary.BigEndian.Uint32(b)
+	b = b[4:]
+	if uint64(len(b)) < uint64(n) {
+		return nil, nil, ErrMalformed
+	}
+	return b[:n:n], b[n:], nil
+}
+
+// Decodes a big-endian int64 from the head of b and returns it along with the remainder.
+func consumeInt64(b []byte) (int64, []byte, error) {
+	if len(b) < 8 {
+		return 0, nil, ErrMalformed
+	}
+	return int64(binary.BigEndian.Uint64(b)), b[8:], nil
+}
+
+// Error reported by the server for a request it could not serve.
+type RemoteError struct {
+	Message string
+}
+
+func (e *RemoteError) Error() string {
+	return fmt.Sprintf("ipc: remote error: %s", e.Message)
+}
@@ -0,0 +1,224 @@
+package ipc
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+// Serves a cache to local clients over one or more listeners.
+type Server struct {
+	cache *goutte.Cache[string, []byte]
+
+	// Upper bound on entries per second streamed to a single snapshot request; 0 means unlimited.
+	// Clients may request a lower rate but never a higher one. Must not be changed while requests are served.
+	MaxSnapshotRate int
+
+	quit chan struct{} // closed by Close to interrupt paced snapshot streams
+
+	mu        sync.Mutex // guards the fields below
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// Returned by Serve after Close has been called.
+var ErrServerClosed = errors.New("ipc: server closed")
+
+// Creates a server exposing the given cache.
+// The server does not own the cache; closing the server leaves the cache running.
+func NewServer(cache *goutte.Cache[string, []byte]) *Server {
+	return &Server{
+		cache:     cache,
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+		quit:      make(chan struct{}),
+	}
+}
+
+// Accepts connections on l and serves each one in its own goroutine.
+// It blocks until l fails or the server is closed, in which case ErrServerClosed is returned.
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrServerClosed
+	}
+	s.listeners[l] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.listeners, l)
+		s.mu.Unlock()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return ErrServerClosed
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go s.serveConn(conn)
+	}
+}
+
+// Stops all listeners, closes active connections and waits for their handlers to return.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.quit)
+	var err error
+	for l := range s.listeners {
+		if cerr := l.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		s.wg.Done()
+	}()
+
+	for {
+		req, err := readFrame(conn)
+		if err != nil {
+			// EOF is a normal disconnect; anything else leaves the stream unsynchronized.
+			return
+		}
+		if len(req) > 0 && req[0] == opSnapshot {
+			if err := s.streamSnapshot(conn, req[1:]); err != nil {
+				return
+			}
+			continue
+		}
+		if err := writeFrame(conn, s.handle(req)); err != nil {
+			return
+		}
+	}
+}
+
+// Executes a single request payload and returns the response payload.
+func (s *Server) handle(req []byte) []byte {
+	if len(req) == 0 {
+		return errorResponse(ErrMalformed)
+	}
+	op, body := req[0], req[1:]
+
+	key, body, err := consumeBytes(body)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	switch op {
+	case opGet:
+		val, ok := s.cache.Get(string(key))
+		if !ok {
+			return []byte{statusNotFound}
+		}
+		return appendBytes([]byte{statusOK}, val)
+	case opSet:
+		ttl, body, err := consumeInt64(body)
+		if err != nil {
+			return errorResponse(err)
+		}
+		val, _, err := consumeBytes(body)
+		if err != nil {
+			return errorResponse(err)
+		}
+		s.cache.SetWithTTL(string(key), val, time.Duration(ttl))
+		return []byte{statusOK}
+	case opDelete:
+		s.cache.Delete(string(key))
+		return []byte{statusOK}
+	default:
+		return errorResponse(ErrMalformed)
+	}
+}
+
+func errorResponse(err error) []byte {
+	return append([]byte{statusError}, err.Error()...)
+}
+
+// Streams every live entry to conn, paced to the negotiated rate, followed by an end frame.
+func (s *Server) streamSnapshot(conn net.Conn, body []byte) error {
+	_, body, err := consumeBytes(body)
+	if err != nil {
+		return writeFrame(conn, errorResponse(err))
+	}
+	requested, _, err := consumeInt64(body)
+	if err != nil {
+		return writeFrame(conn, errorResponse(err))
+	}
+
+	rate := int64(s.MaxSnapshotRate)
+	if requested > 0 && (rate <= 0 || requested < rate) {
+		rate = requested
+	}
+	var interval time.Duration
+	if rate > 0 {
+		interval = time.Second / time.Duration(rate)
+	}
+
+	start := time.Now()
+	for i, e := range s.cache.Snapshot() {
+		if interval > 0 {
+			if wait := time.Until(start.Add(time.Duration(i) * interval)); wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-s.quit:
+					return ErrServerClosed
+				}
+			}
+		}
+
+		var exp int64
+		if !e.Expiration.IsZero() {
+			exp = e.Expiration.UnixNano()
+		}
+		frame := appendBytes([]byte{statusOK}, []byte(e.Key))
+		frame = binary.BigEndian.AppendUint64(frame, uint64(exp))
+		frame = appendBytes(frame, e.Value)
+		if err := writeFrame(conn, frame); err != nil {
+			return err
+		}
+	}
+	return writeFrame(conn, []byte{statusEnd})
+}
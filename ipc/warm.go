@@ -0,0 +1,84 @@
+package ipc
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+// Streams every live entry of the remote cache, from least to most recently used, calling fn for each.
+// The server sends at most rate entries per second (0 leaves pacing to the server's MaxSnapshotRate).
+// A zero expiration means the entry has no TTL. The client cannot be used for other requests meanwhile.
+func (c *Client) Snapshot(rate int, fn func(key string, value []byte, expiration time.Time)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	req := appendBytes([]byte{opSnapshot}, nil)
+	req = binary.BigEndian.AppendUint64(req, uint64(rate))
+	if err := writeFrame(c.conn, req); err != nil {
+		return err
+	}
+
+	for {
+		frame, err := readFrame(c.conn)
+		if err != nil {
+			return err
+		}
+		if len(frame) == 0 {
+			return ErrMalformed
+		}
+		switch frame[0] {
+		case statusEnd:
+			return nil
+		case statusError:
+			return &RemoteError{Message: string(frame[1:])}
+		case statusOK:
+		default:
+			return ErrMalformed
+		}
+
+		key, rest, err := consumeBytes(frame[1:])
+		if err != nil {
+			return err
+		}
+		exp, rest, err := consumeInt64(rest)
+		if err != nil {
+			return err
+		}
+		value, _, err := consumeBytes(rest)
+		if err != nil {
+			return err
+		}
+		var expiration time.Time
+		if exp != 0 {
+			expiration = time.Unix(0, exp)
+		}
+		fn(string(key), value, expiration)
+	}
+}
+
+// Fills cache with the contents of the peer serving the Unix domain socket at path, preserving
+// recency order and remaining TTLs, so a newly started process begins warm. Entries that expire in
+// transit are skipped. The peer streams at most rate entries per second; 0 defers to the server limit.
+// It returns the number of entries loaded.
+func WarmFromPeer(path string, cache *goutte.Cache[string, []byte], rate int) (int, error) {
+	client, err := Dial(path)
+	if err != nil {
+		return 0, err
+	}
+	defer client.Close()
+
+	loaded := 0
+	err = client.Snapshot(rate, func(key string, value []byte, expiration time.Time) {
+		var ttl time.Duration
+		if !expiration.IsZero() {
+			if ttl = time.Until(expiration); ttl <= 0 {
+				return
+			}
+		}
+		cache.SetWithTTL(key, value, ttl)
+		loaded++
+	})
+	return loaded, err
+}
@@ -0,0 +1,62 @@
+package ipc_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+	"github.com/shellkah/goutte/ipc"
+)
+
+func TestWarmFromPeer(t *testing.T) {
+	peer := goutte.NewCache[string, []byte](10)
+	defer peer.Close()
+	peer.Set("a", []byte("1"))
+	peer.SetWithTTL("b", []byte("2"), time.Minute)
+	peer.Set("c", []byte("3"))
+	peer.Get("a") // recency order is now b, c, a
+	_, path := listen(t, peer)
+
+	cache := goutte.NewCache[string, []byte](3)
+	defer cache.Close()
+	n, err := ipc.WarmFromPeer(path, cache, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("Expected 3 entries loaded, got %d", n)
+	}
+
+	entries := cache.Snapshot()
+	if len(entries) != 3 || entries[0].Key != "b" || entries[1].Key != "c" || entries[2].Key != "a" {
+		t.Fatalf("Expected recency order b, c, a, got %+v", entries)
+	}
+	if entries[0].Expiration.IsZero() || time.Until(entries[0].Expiration) > time.Minute {
+		t.Errorf("Expected key 'b' to keep its remaining TTL, got expiration %v", entries[0].Expiration)
+	}
+	if string(entries[2].Value) != "1" {
+		t.Errorf("Expected key 'a' to have value '1', got %q", entries[2].Value)
+	}
+}
+
+func TestWarmFromPeerRateLimit(t *testing.T) {
+	peer := goutte.NewCache[string, []byte](10)
+	defer peer.Close()
+	for _, k := range []string{"a", "b", "c", "d", "e"} {
+		peer.Set(k, []byte(k))
+	}
+	srv, path := listen(t, peer)
+	srv.MaxSnapshotRate = 1000
+
+	cache := goutte.NewCache[string, []byte](10)
+	defer cache.Close()
+
+	// The client asks for 50 entries per second, below the server cap: 5 entries span 80ms.
+	start := time.Now()
+	if _, err := ipc.WarmFromPeer(path, cache, 50); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("Expected the snapshot to be paced to at least 70ms, took %v", elapsed)
+	}
+}
@@ -0,0 +1,162 @@
+package goutte
+
+import "time"
+
+// Tuning for the background expiration goroutine (the janitor); see WithJanitor.
+type JanitorConfig struct {
+	// Maximum number of expired entries removed per locked pass, so a burst of expirations does not
+	// hold the lock for long. 0 removes every due entry in one pass. Entries sharing a deadline, such
+	// as a group set by SetGroupDeadline, are removed in the same pass even past the limit.
+	BatchSize int
+
+	// Number of overdue entries at which the janitor is considered to be falling behind. While degraded,
+	// every SetWithTTL removes up to SetCleanup overdue entries itself; lookups already skip and remove
+	// expired entries they encounter. The cache recovers once the backlog drops to half the threshold.
+	// 0 disables degradation tracking.
+	BacklogThreshold int
+
+	// Overdue entries removed by each write while degraded. Defaults to 8.
+	SetCleanup int
+
+	// Called from the janitor goroutine, outside the lock, whenever the cache enters or leaves
+	// degraded mode. Useful to surface a health warning.
+	OnHealthChange func(JanitorStats)
+}
+
+// Snapshot of the janitor state, as returned by JanitorStats.
+type JanitorStats struct {
+	Pending  int    // expiration entries queued in the heap, including canceled ones awaiting cleanup
+	Backlog  int    // entries past their deadline that have not been removed yet
+	Degraded bool   // whether writes are currently helping with cleanup
+	Passes   uint64 // completed janitor passes
+	Removed  uint64 // expired entries removed by the janitor and by writes helping it
+}
+
+// Default JanitorConfig.SetCleanup.
+const defaultSetCleanup = 8
+
+type janitorState struct {
+	JanitorConfig
+	degraded bool
+	passes   uint64
+	removed  uint64
+}
+
+// Configures the background expiration goroutine; see JanitorConfig.
+func WithJanitor[K comparable, V any](cfg JanitorConfig) Option[K, V] {
+	if cfg.BatchSize < 0 || cfg.BacklogThreshold < 0 || cfg.SetCleanup < 0 {
+		panic("janitor settings must not be negative")
+	}
+	if cfg.SetCleanup == 0 {
+		cfg.SetCleanup = defaultSetCleanup
+	}
+	return func(c *Cache[K, V]) {
+		c.janitor.JanitorConfig = cfg
+	}
+}
+
+// Returns the current janitor state, including the number of overdue entries.
+func (c *Cache[K, V]) JanitorStats() JanitorStats {
+	c.lock()
+	defer c.unlock()
+
+	return c.janitorStatsLocked(0)
+}
+
+func (c *Cache[K, V]) janitorStatsLocked(backlogLimit int) JanitorStats {
+	pending := c.expHeap.Len()
+	if c.wheel != nil {
+		pending = c.wheel.len()
+	}
+	return JanitorStats{
+		Pending:  pending,
+		Backlog:  c.countOverdueLocked(backlogLimit),
+		Degraded: c.janitor.degraded,
+		Passes:   c.janitor.passes,
+		Removed:  c.janitor.removed,
+	}
+}
+
+// Counts live heap entries whose deadline has passed, stopping at limit if it is positive.
+// Only the part of the heap that is already due is visited.
+func (c *Cache[K, V]) countOverdueLocked(limit int) int {
+	now := c.clock.Now()
+	if c.wheel != nil {
+		c.wheel.advance(c.wheel.ticks(now, false))
+		if limit > 0 {
+			return min(limit, c.wheel.due.Len())
+		}
+		return c.wheel.due.Len()
+	}
+	count := 0
+	stack := []int{0}
+	for len(stack) > 0 && (limit <= 0 || count < limit) {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if i >= len(c.expHeap) || now.Before(c.expHeap[i].expiration) {
+			continue
+		}
+		if !c.expHeap[i].canceled {
+			count++
+		}
+		stack = append(stack, 2*i+1, 2*i+2)
+	}
+	return count
+}
+
+// Counts the entries expired at now but not removed yet, live ones and tombstones whose window
+// elapsed apart, in O(due entries): the heap is only explored below slots that are due, and the
+// timing wheel only through its due list, with the same precision as expireWheelLocked.
+func (c *Cache[K, V]) countDueLocked(now time.Time) (live, tombstones int) {
+	count := func(ent *entry[K, V], expiration time.Time) {
+		switch {
+		case !ent.expiration.Equal(expiration) || now.Before(expiration):
+		case ent.tombstone:
+			tombstones++
+		default:
+			live++
+		}
+	}
+	if w := c.wheel; w != nil {
+		w.advance(w.ticks(now, false))
+		for e := w.due.Front(); e != nil; e = e.Next() {
+			ent := c.cache[e.Value.(*wheelTimer[K]).key].Value.(*entry[K, V])
+			count(ent, ent.expiration)
+		}
+		return live, tombstones
+	}
+	stack := []int{0}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if i >= len(c.expHeap) || now.Before(c.expHeap[i].expiration) {
+			continue
+		}
+		if e := c.expHeap[i]; !e.canceled {
+			if ele, ok := c.cache[e.key]; ok {
+				count(ele.Value.(*entry[K, V]), e.expiration)
+			}
+		}
+		stack = append(stack, 2*i+1, 2*i+2)
+	}
+	return live, tombstones
+}
+
+// Enters or leaves degraded mode based on the current backlog.
+// It reports whether the state changed and OnHealthChange should be called.
+func (c *Cache[K, V]) updateJanitorHealthLocked() (bool, JanitorStats) {
+	threshold := c.janitor.BacklogThreshold
+	if threshold == 0 {
+		return false, JanitorStats{}
+	}
+	stats := c.janitorStatsLocked(threshold)
+	switch {
+	case !c.janitor.degraded && stats.Backlog >= threshold:
+		c.janitor.degraded = true
+	case c.janitor.degraded && stats.Backlog <= threshold/2:
+		c.janitor.degraded = false
+	default:
+		return false, stats
+	}
+	return c.janitor.OnHealthChange != nil, c.janitorStatsLocked(0)
+}
@@ -0,0 +1,64 @@
+package goutte_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheJanitorDegradation(t *testing.T) {
+	var mu sync.Mutex
+	var changes []goutte.JanitorStats
+	onHealthChange := func(stats goutte.JanitorStats) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, stats)
+	}
+	cache := goutte.NewCache[int, int](2000, goutte.WithJanitor[int, int](goutte.JanitorConfig{
+		BatchSize:        1,
+		BacklogThreshold: 50,
+		OnHealthChange:   onHealthChange,
+	}))
+	defer cache.Close()
+
+	for i := 0; i < 1000; i++ {
+		cache.SetWithTTL(i, i, time.Millisecond)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if stats := cache.JanitorStats(); stats.Removed == 1000 && !stats.Degraded {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	stats := cache.JanitorStats()
+	if stats.Removed != 1000 || stats.Backlog != 0 || stats.Degraded {
+		t.Fatalf("Expected all 1000 entries removed and the janitor healthy, got %+v", stats)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(changes) != 2 || !changes[0].Degraded || changes[0].Backlog < 50 || changes[1].Degraded {
+		t.Errorf("Expected one degraded and one recovered notification, got %+v", changes)
+	}
+}
+
+func TestCacheJanitorStats(t *testing.T) {
+	cache := goutte.NewCache[string, int](10)
+	defer cache.Close()
+	cache.SetWithTTL("a", 1, time.Hour)
+	cache.SetWithTTL("b", 2, 10*time.Millisecond)
+
+	if stats := cache.JanitorStats(); stats.Pending != 2 || stats.Backlog != 0 {
+		t.Errorf("Expected 2 pending and no backlog, got %+v", stats)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if stats := cache.JanitorStats(); stats.Pending != 1 || stats.Removed != 1 || stats.Passes == 0 {
+		t.Errorf("Expected the janitor to have removed key 'b', got %+v", stats)
+	}
+}
@@ -0,0 +1,133 @@
+package goutte
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// Access counters for a single key, as reported by TopKeys.
+// Hits and Misses count accesses since the key entered the bounded table. When a key displaces a less
+// frequent one, it inherits the displaced count as Error, so the key may have been accessed up to Error
+// more times before it was tracked.
+type KeyStats[K comparable] struct {
+	Key    K
+	Hits   uint64
+	Misses uint64
+	Error  uint64 // overestimation bound inherited on admission into the table
+}
+
+// Counter slot in the space-saving table.
+type keyCounter[K comparable] struct {
+	stats KeyStats[K]
+	index int // position in keyCounterHeap
+}
+
+func (kc *keyCounter[K]) total() uint64 {
+	return kc.stats.Hits + kc.stats.Misses + kc.stats.Error
+}
+
+// Min-heap of counters ordered by total access count.
+type keyCounterHeap[K comparable] []*keyCounter[K]
+
+func (h keyCounterHeap[K]) Len() int { return len(h) }
+
+func (h keyCounterHeap[K]) Less(i, j int) bool { return h[i].total() < h[j].total() }
+
+func (h keyCounterHeap[K]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *keyCounterHeap[K]) Push(x interface{}) {
+	kc := x.(*keyCounter[K])
+	kc.index = len(*h)
+	*h = append(*h, kc)
+}
+
+func (h *keyCounterHeap[K]) Pop() interface{} {
+	old := *h
+	n := len(old)
+	kc := old[n-1]
+	kc.index = -1 // for safety
+	*h = old[0 : n-1]
+	return kc
+}
+
+// Bounded top-K table of per-key counters using the space-saving algorithm.
+type keyStatsTracker[K comparable] struct {
+	size     int
+	match    func(K) bool // keys to track; nil tracks all keys
+	counters map[K]*keyCounter[K]
+	heap     keyCounterHeap[K]
+}
+
+func newKeyStatsTracker[K comparable](size int, match func(K) bool) *keyStatsTracker[K] {
+	return &keyStatsTracker[K]{
+		size:     size,
+		match:    match,
+		counters: make(map[K]*keyCounter[K], size),
+	}
+}
+
+func (t *keyStatsTracker[K]) record(key K, hit bool) {
+	if t.match != nil && !t.match(key) {
+		return
+	}
+
+	kc, ok := t.counters[key]
+	if !ok {
+		if len(t.heap) < t.size {
+			kc = &keyCounter[K]{stats: KeyStats[K]{Key: key}}
+			heap.Push(&t.heap, kc)
+		} else {
+			// Replace the least accessed key; the newcomer inherits its count as an error bound.
+			kc = t.heap[0]
+			delete(t.counters, kc.stats.Key)
+			n := kc.total()
+			kc.stats = KeyStats[K]{Key: key, Error: n}
+		}
+		t.counters[key] = kc
+	}
+
+	if hit {
+		kc.stats.Hits++
+	} else {
+		kc.stats.Misses++
+	}
+	heap.Fix(&t.heap, kc.index)
+}
+
+// Tracks hit and miss counts per key in a bounded table holding at most size keys, so memory stays
+// constant regardless of the key space. When the table is full, a newly seen key replaces the least
+// accessed one (space-saving algorithm), so frequently accessed keys are retained. If match is non-nil,
+// only keys for which it returns true are tracked. The counters are read with TopKeys.
+func WithKeyStats[K comparable, V any](size int, match func(K) bool) Option[K, V] {
+	if size <= 0 {
+		panic("key stats size must be greater than zero")
+	}
+	return func(c *Cache[K, V]) {
+		c.keyStats = newKeyStatsTracker(size, match)
+	}
+}
+
+// Returns the tracked per-key counters, most accessed first (counting the inherited Error).
+// It returns nil if key statistics are not enabled with WithKeyStats.
+func (c *Cache[K, V]) TopKeys() []KeyStats[K] {
+	c.lock()
+	defer c.unlock()
+
+	if c.keyStats == nil {
+		return nil
+	}
+	counters := make([]*keyCounter[K], len(c.keyStats.heap))
+	copy(counters, c.keyStats.heap)
+	sort.Slice(counters, func(i, j int) bool {
+		return counters[i].total() > counters[j].total()
+	})
+	stats := make([]KeyStats[K], len(counters))
+	for i, kc := range counters {
+		stats[i] = kc.stats
+	}
+	return stats
+}
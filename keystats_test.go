@@ -0,0 +1,47 @@
+package goutte_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheTopKeys(t *testing.T) {
+	cache := goutte.NewCache[string, int](10, goutte.WithKeyStats[string, int](2, nil))
+	defer cache.Close()
+	cache.Set("hot", 1)
+
+	for i := 0; i < 10; i++ {
+		cache.Get("hot")
+	}
+	cache.Get("cold1")
+	cache.Get("cold2")
+	cache.Get("cold3")
+
+	stats := cache.TopKeys()
+	if len(stats) != 2 {
+		t.Fatalf("Expected the table to be bounded to 2 keys, got %+v", stats)
+	}
+	if stats[0].Key != "hot" || stats[0].Hits != 10 || stats[0].Misses != 0 || stats[0].Error != 0 {
+		t.Errorf("Expected 'hot' first with 10 exact hits, got %+v", stats[0])
+	}
+	// The single remaining slot is shared by the cold keys; the last one seen holds it.
+	if stats[1].Key != "cold3" || stats[1].Misses != 1 || stats[1].Error != 2 {
+		t.Errorf("Expected 'cold3' with 1 miss and error 2, got %+v", stats[1])
+	}
+}
+
+func TestCacheTopKeysPredicate(t *testing.T) {
+	match := func(k string) bool { return strings.HasPrefix(k, "user:") }
+	cache := goutte.NewCache[string, int](10, goutte.WithKeyStats[string, int](10, match))
+	defer cache.Close()
+
+	cache.Get("user:1")
+	cache.Get("session:1")
+
+	stats := cache.TopKeys()
+	if len(stats) != 1 || stats[0].Key != "user:1" || stats[0].Misses != 1 {
+		t.Errorf("Expected only 'user:1' to be tracked, got %+v", stats)
+	}
+}
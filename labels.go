@@ -0,0 +1,79 @@
+package goutte
+
+// Label under which callers beyond the cardinality limit are aggregated.
+const OverflowLabel = "other"
+
+// Default maximum number of distinct caller labels; see WithMaxCallerLabels.
+const defaultMaxCallerLabels = 64
+
+// Hit and miss counts attributed to a caller label.
+type CallerStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// Per-caller counters with bounded cardinality. The map is allocated on first use.
+type callerLabels struct {
+	max    int
+	counts map[string]*CallerStats
+}
+
+func (cl *callerLabels) record(label string, hit bool) {
+	if cl.counts == nil {
+		if cl.max == 0 {
+			cl.max = defaultMaxCallerLabels
+		}
+		cl.counts = make(map[string]*CallerStats)
+	}
+	cs, ok := cl.counts[label]
+	if !ok {
+		// One slot is reserved for the overflow label.
+		if label != OverflowLabel && len(cl.counts) >= cl.max-1 {
+			label = OverflowLabel
+			cs = cl.counts[label]
+		}
+		if cs == nil {
+			cs = &CallerStats{}
+			cl.counts[label] = cs
+		}
+	}
+	if hit {
+		cs.Hits++
+	} else {
+		cs.Misses++
+	}
+}
+
+// Limits the number of distinct labels tracked by GetLabeled, including OverflowLabel.
+// Once the limit is reached, new labels are counted under OverflowLabel. The default is 64.
+func WithMaxCallerLabels[K comparable, V any](n int) Option[K, V] {
+	if n < 2 {
+		panic("caller label limit must be at least 2")
+	}
+	return func(c *Cache[K, V]) {
+		c.labels.max = n
+	}
+}
+
+// Retrieves the value associated with the given key like Get, attributing the hit or miss to the
+// given caller label so a shared cache can report its effectiveness per subsystem.
+func (c *Cache[K, V]) GetLabeled(key K, label string) (V, bool) {
+	c.lock()
+	defer c.unlock()
+
+	val, ok := c.getLocked(key)
+	c.labels.record(label, ok)
+	return val, ok
+}
+
+// Returns a copy of the hit and miss counters recorded by GetLabeled, keyed by caller label.
+func (c *Cache[K, V]) CallerStats() map[string]CallerStats {
+	c.lock()
+	defer c.unlock()
+
+	stats := make(map[string]CallerStats, len(c.labels.counts))
+	for label, cs := range c.labels.counts {
+		stats[label] = *cs
+	}
+	return stats
+}
@@ -0,0 +1,49 @@
+package goutte_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheGetLabeled(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+	cache.Set("a", 1)
+
+	if val, ok := cache.GetLabeled("a", "search"); !ok || val != 1 {
+		t.Errorf("Expected key 'a' to have value 1, got %v (found: %v)", val, ok)
+	}
+	cache.GetLabeled("b", "search")
+	cache.GetLabeled("a", "checkout")
+	cache.Get("a") // unlabeled reads are not attributed
+
+	stats := cache.CallerStats()
+	if len(stats) != 2 {
+		t.Fatalf("Expected 2 labels, got %+v", stats)
+	}
+	if s := stats["search"]; s.Hits != 1 || s.Misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss for 'search', got %+v", s)
+	}
+	if s := stats["checkout"]; s.Hits != 1 || s.Misses != 0 {
+		t.Errorf("Expected 1 hit for 'checkout', got %+v", s)
+	}
+}
+
+func TestCacheGetLabeledCardinality(t *testing.T) {
+	cache := goutte.NewCache[string, int](2, goutte.WithMaxCallerLabels[string, int](3))
+	defer cache.Close()
+
+	for i := 0; i < 10; i++ {
+		cache.GetLabeled("a", fmt.Sprintf("caller-%d", i))
+	}
+
+	stats := cache.CallerStats()
+	if len(stats) != 3 {
+		t.Fatalf("Expected labels to be capped at 3, got %+v", stats)
+	}
+	if s := stats[goutte.OverflowLabel]; s.Misses != 8 {
+		t.Errorf("Expected 8 misses under the overflow label, got %+v", s)
+	}
+}
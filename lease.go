@@ -0,0 +1,135 @@
+package goutte
+
+import (
+	"context"
+	"time"
+)
+
+// Default lifetime of a lease, see WithLeaseTTL.
+const defaultLeaseTTL = 10 * time.Second
+
+// Obligation to fill a missing key, handed out by GetOrLease to the first caller that misses.
+// The holder must eventually call either Fill or Abandon. While the lease is outstanding, other callers
+// of GetOrLease receive no lease and may either proceed without the value or block in AwaitLease.
+// Writing or deleting the key revokes the lease, so a holder cannot overwrite a newer value with a
+// stale one; Fill then reports false. So does letting the lease expire, see WithLeaseTTL.
+type Lease[K comparable, V any] struct {
+	c        *Cache[K, V]
+	key      K
+	expires  time.Time     // after which the lease is revoked
+	done     chan struct{} // closed when the lease is filled, abandoned or revoked
+	released bool          // guarded by c.mu
+}
+
+// Sets how long a lease granted by GetOrLease stays valid, 10 seconds by default. Once it elapses,
+// the lease is revoked, so a holder that crashed or forgot to call Fill or Abandon does not keep
+// other callers from filling the key: the next GetOrLease grants a new lease, and AwaitLease returns.
+func WithLeaseTTL[K comparable, V any](ttl time.Duration) Option[K, V] {
+	if ttl <= 0 {
+		panic("lease TTL must be greater than zero")
+	}
+	return func(c *Cache[K, V]) {
+		c.leaseTTL = ttl
+	}
+}
+
+// Retrieves the value for key, or grants a lease to fill it.
+// On a hit it returns the value and a nil lease. On a miss it returns a lease if no other caller holds
+// one for the key and the key is not soft-deleted, and a nil lease otherwise.
+func (c *Cache[K, V]) GetOrLease(key K) (V, bool, *Lease[K, V]) {
+	c.lock()
+	defer c.unlock()
+
+	if ele := c.lookupLocked(key); ele != nil {
+		c.hitLocked(ele)
+		return ele.Value.(*entry[K, V]).value, true, nil
+	}
+	c.missLocked(key)
+
+	var zero V
+	c.expireLeaseLocked(key)
+	if _, held := c.leases[key]; held || c.tombstonedLocked(key) {
+		return zero, false, nil
+	}
+	if c.leases == nil {
+		c.leases = make(map[K]*Lease[K, V])
+	}
+	ttl := c.leaseTTL
+	if ttl == 0 {
+		ttl = defaultLeaseTTL
+	}
+	l := &Lease[K, V]{c: c, key: key, expires: c.clock.Now().Add(ttl), done: make(chan struct{})}
+	c.leases[key] = l
+	return zero, false, l
+}
+
+// Waits until the outstanding lease for key, if any, is released or expires, then retrieves the key.
+// The second result is false if the lease was abandoned, revoked or expired without a value being
+// stored. It returns ctx.Err() if the context is done first.
+func (c *Cache[K, V]) AwaitLease(ctx context.Context, key K) (V, bool, error) {
+	c.lock()
+	c.expireLeaseLocked(key)
+	l := c.leases[key]
+	var timer Timer
+	if l != nil {
+		timer = c.clock.NewTimer(l.expires.Sub(c.clock.Now()))
+	}
+	c.unlock()
+
+	if l != nil {
+		defer timer.Stop()
+		select {
+		case <-l.done:
+		case <-timer.C():
+			c.lock()
+			c.expireLeaseLocked(key)
+			c.unlock()
+		case <-ctx.Done():
+			var zero V
+			return zero, false, ctx.Err()
+		}
+	}
+
+	val, ok := c.Get(key)
+	return val, ok, nil
+}
+
+// Stores the value with an optional TTL and releases the lease.
+// It reports false, storing nothing, if the lease was already released or revoked.
+func (l *Lease[K, V]) Fill(value V, ttl time.Duration) bool {
+	c := l.c
+	c.lock()
+	defer c.unlock()
+
+	c.expireLeaseLocked(l.key)
+	if l.released {
+		return false
+	}
+	// Writing the key revokes the lease.
+	c.writeLocked(l.key, value, ttl)
+	return true
+}
+
+// Releases the lease without storing a value, letting another caller obtain it.
+func (l *Lease[K, V]) Abandon() {
+	l.c.lock()
+	defer l.c.unlock()
+
+	l.c.revokeLeaseLocked(l.key)
+}
+
+// Releases the outstanding lease for key, if any, waking its waiters.
+func (c *Cache[K, V]) revokeLeaseLocked(key K) {
+	if l, ok := c.leases[key]; ok {
+		l.released = true
+		close(l.done)
+		delete(c.leases, key)
+	}
+}
+
+// Revokes the outstanding lease for key if it has expired.
+func (c *Cache[K, V]) expireLeaseLocked(key K) {
+	if l, ok := c.leases[key]; ok && !c.clock.Now().Before(l.expires) {
+		c.revokeLeaseLocked(key)
+	}
+}
@@ -0,0 +1,131 @@
+package goutte_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheGetOrLease(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+
+	_, found, lease := cache.GetOrLease("a")
+	if found || lease == nil {
+		t.Fatalf("Expected the first miss to receive a lease, got found=%v lease=%v", found, lease)
+	}
+
+	// A second caller misses without a lease.
+	if _, found, other := cache.GetOrLease("a"); found || other != nil {
+		t.Errorf("Expected a second caller to get no lease, got found=%v lease=%v", found, other)
+	}
+
+	result := make(chan int, 1)
+	go func() {
+		val, _, _ := cache.AwaitLease(context.Background(), "a")
+		result <- val
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	if !lease.Fill(1, 0) {
+		t.Fatal("Expected Fill to store the value")
+	}
+	if val := <-result; val != 1 {
+		t.Errorf("Expected the waiter to see value 1, got %v", val)
+	}
+	if lease.Fill(2, 0) {
+		t.Error("Expected a second Fill to be rejected")
+	}
+
+	if val, found, lease := cache.GetOrLease("a"); !found || val != 1 || lease != nil {
+		t.Errorf("Expected a hit with value 1 and no lease, got %v (found: %v, lease: %v)", val, found, lease)
+	}
+}
+
+func TestCacheLeaseAbandon(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+
+	_, _, lease := cache.GetOrLease("a")
+	lease.Abandon()
+
+	if _, found, err := cache.AwaitLease(context.Background(), "a"); found || err != nil {
+		t.Errorf("Expected no value after Abandon, got found=%v err=%v", found, err)
+	}
+	if _, _, next := cache.GetOrLease("a"); next == nil {
+		t.Error("Expected a new lease after the previous one was abandoned")
+	}
+}
+
+func TestCacheLeaseRevokedByDelete(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+
+	_, _, lease := cache.GetOrLease("a")
+	cache.Delete("a")
+
+	if lease.Fill(1, 0) {
+		t.Error("Expected Fill to be rejected after the key was deleted")
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected the stale fill not to be stored")
+	}
+}
+
+func TestCacheAwaitLeaseContext(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+	cache.GetOrLease("a")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := cache.AwaitLease(ctx, "a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestCacheLeaseExpires(t *testing.T) {
+	clock := goutte.NewManualClock(time.Unix(0, 0))
+	cache := goutte.NewCache(2, goutte.WithClock[string, int](clock), goutte.WithLeaseTTL[string, int](time.Second))
+	defer cache.Close()
+
+	_, _, abandoned := cache.GetOrLease("a")
+	if abandoned == nil {
+		t.Fatal("Expected the first miss to receive a lease")
+	}
+	result := make(chan bool, 1)
+	go func() {
+		_, found, err := cache.AwaitLease(context.Background(), "a")
+		result <- found || err != nil
+	}()
+	if _, _, lease := cache.GetOrLease("a"); lease != nil {
+		t.Error("Expected no lease while the first one is valid")
+	}
+
+	// The holder never fills the key: once the lease expires, waiters return and the key can be leased again.
+	clock.Advance(2 * time.Second)
+	select {
+	case failed := <-result:
+		if failed {
+			t.Error("Expected AwaitLease to return without a value or error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected AwaitLease to return once the lease expired")
+	}
+	_, _, lease := cache.GetOrLease("a")
+	if lease == nil {
+		t.Fatal("Expected an expired lease to be granted again")
+	}
+	if abandoned.Fill(1, 0) {
+		t.Error("Expected Fill on an expired lease to be rejected")
+	}
+	if !lease.Fill(2, 0) {
+		t.Error("Expected Fill on the new lease to store the value")
+	}
+	if val, _ := cache.Get("a"); val != 2 {
+		t.Errorf("Expected 2, got %d", val)
+	}
+}
@@ -0,0 +1,175 @@
+package goutte
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Fetches the value of a key missing from a LoadingCache.
+type LoaderFunc[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// Cache with read-through semantics: Get loads missing keys with a LoaderFunc and caches the result.
+// Concurrent Gets missing on the same key share a single load, as in GetOrCompute. The embedded Cache
+// gives direct access to the cached entries, e.g. to Set a fresh value or Delete a stale one.
+type LoadingCache[K comparable, V any] struct {
+	*Cache[K, V]
+	loader  LoaderFunc[K, V]
+	ttl     func(K, V) time.Duration // TTL of loaded entries; nil for none
+	errTTL  time.Duration
+	errSize int
+	errs    *Cache[K, error] // recent loader errors; nil unless WithErrorTTL is set
+	tracer  LoadTracer[K]    // nil unless WithLoadTracer is set
+
+	refreshAfter time.Duration
+}
+
+// Instruments the reads and loads of a LoadingCache, e.g. to record them as trace spans; the
+// otelgoutte module provides an OpenTelemetry implementation. Each method is called when the operation
+// starts, and returns the context to run it with and a function to call when it ends.
+type LoadTracer[K comparable] interface {
+	// Called by Get. end receives whether the value was served from the cache, without waiting
+	// for a load, and the error returned by Get.
+	StartGet(ctx context.Context, key K) (_ context.Context, end func(hit bool, err error))
+
+	// Called before each loader invocation, including background refreshes. end receives the
+	// error returned by the loader.
+	StartLoad(ctx context.Context, key K) (_ context.Context, end func(err error))
+}
+
+// Configures a LoadingCache.
+type LoadingOption[K comparable, V any] func(*LoadingCache[K, V])
+
+// Sets the TTL of every loaded entry. By default, loaded entries get the default TTL of the cache, if any.
+func WithLoadTTL[K comparable, V any](ttl time.Duration) LoadingOption[K, V] {
+	return WithLoadTTLFunc(func(K, V) time.Duration { return ttl })
+}
+
+// Computes the TTL of each loaded entry from its key and value, e.g. from an expiry carried by the
+// value. A non-positive TTL stores the entry without expiration.
+func WithLoadTTLFunc[K comparable, V any](ttl func(key K, value V) time.Duration) LoadingOption[K, V] {
+	return func(lc *LoadingCache[K, V]) {
+		lc.ttl = ttl
+	}
+}
+
+// Caches loader errors for ttl, in a separate table holding at most size keys, so a failing backend
+// is not queried on every Get. By default, errors are returned to the callers sharing the failed load
+// only. Cached errors are not cleared by writing the key: a value stored with Set is served first.
+func WithErrorTTL[K comparable, V any](ttl time.Duration, size int) LoadingOption[K, V] {
+	if ttl <= 0 || size <= 0 {
+		panic("error TTL and size must be greater than zero")
+	}
+	return func(lc *LoadingCache[K, V]) {
+		lc.errTTL = ttl
+		lc.errSize = size
+	}
+}
+
+// Refreshes loaded entries in the background when they are read more than d after being loaded,
+// so hot keys are reloaded before they expire and readers never wait for them. The read that triggers
+// the refresh, and those following it until the new value is stored, get the current value. A failed
+// refresh, including one whose loader panics, keeps the current value and is retried on a read at
+// least d later. d is typically a fraction of the load TTL.
+func WithRefreshAfter[K comparable, V any](d time.Duration) LoadingOption[K, V] {
+	if d <= 0 {
+		panic("refresh delay must be greater than zero")
+	}
+	return func(lc *LoadingCache[K, V]) {
+		lc.refreshAfter = d
+	}
+}
+
+// Instruments Get and the loader calls with tracer, so misses and slow loads show up in traces.
+func WithLoadTracer[K comparable, V any](tracer LoadTracer[K]) LoadingOption[K, V] {
+	return func(lc *LoadingCache[K, V]) {
+		lc.tracer = tracer
+	}
+}
+
+// Wraps cache so that Get fills missing keys with loader. The loading cache takes ownership of cache:
+// closing it closes cache.
+func NewLoadingCache[K comparable, V any](cache *Cache[K, V], loader LoaderFunc[K, V], opts ...LoadingOption[K, V]) *LoadingCache[K, V] {
+	if loader == nil {
+		panic("loader must not be nil")
+	}
+	lc := &LoadingCache[K, V]{Cache: cache, loader: loader}
+	for _, opt := range opts {
+		opt(lc)
+	}
+	cache.lock()
+	cache.refreshAfter = lc.refreshAfter
+	cache.unlock()
+	if lc.errTTL > 0 {
+		lc.errs = NewCache[K, error](lc.errSize)
+	}
+	return lc
+}
+
+// Retrieves the value for key, loading and caching it if it is missing.
+// Loader errors are returned as is and not cached, unless WithErrorTTL is set. While the key is
+// soft-deleted, ErrSoftDeleted is returned without loading. The load runs with the context of the
+// caller that triggered it; other callers waiting for it return ctx.Err() if their own context is
+// done first.
+func (lc *LoadingCache[K, V]) Get(ctx context.Context, key K) (V, error) {
+	load := func(ctx context.Context) (V, time.Duration, error) {
+		return lc.load(ctx, key)
+	}
+	if lc.tracer == nil {
+		val, _, err := lc.coalesce(ctx, key, load)
+		return val, err
+	}
+	ctx, end := lc.tracer.StartGet(ctx, key)
+	val, hit, err := lc.coalesce(ctx, key, load)
+	end(hit, err)
+	return val, err
+}
+
+// Runs the loader for a missing key, consulting and feeding the error cache.
+func (lc *LoadingCache[K, V]) load(ctx context.Context, key K) (V, time.Duration, error) {
+	if lc.errs != nil {
+		if err, ok := lc.errs.Get(key); ok {
+			var zero V
+			return zero, 0, err
+		}
+	}
+	val, err := lc.invoke(ctx, key)
+	if err != nil {
+		if lc.errs != nil {
+			lc.errs.SetWithTTL(key, err, lc.errTTL)
+		}
+		return val, 0, err
+	}
+	ttl := time.Duration(-1) // default TTL
+	if lc.ttl != nil {
+		ttl = max(lc.ttl(key, val), 0)
+	}
+	return val, ttl, nil
+}
+
+// Calls the loader, reporting the call to the tracer if one is set, as failed if the loader panics.
+func (lc *LoadingCache[K, V]) invoke(ctx context.Context, key K) (val V, err error) {
+	if lc.tracer != nil {
+		var end func(error)
+		ctx, end = lc.tracer.StartLoad(ctx, key)
+		completed := false
+		defer func() {
+			if !completed {
+				err = fmt.Errorf("goutte: loading key %v panicked", key)
+			}
+			end(err)
+		}()
+		val, err = lc.loader(ctx, key)
+		completed = true
+		return val, err
+	}
+	return lc.loader(ctx, key)
+}
+
+// Stops the background goroutines of the cache.
+func (lc *LoadingCache[K, V]) Close() {
+	lc.Cache.Close()
+	if lc.errs != nil {
+		lc.errs.Close()
+	}
+}
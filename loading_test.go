@@ -0,0 +1,250 @@
+package goutte_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestLoadingCache(t *testing.T) {
+	var loads atomic.Int32
+	loader := func(ctx context.Context, key string) (string, error) {
+		loads.Add(1)
+		if key == "missing" {
+			return "", goutte.ErrNotFound
+		}
+		return "value-" + key, nil
+	}
+	cache := goutte.NewLoadingCache(goutte.NewCache[string, string](2), loader,
+		goutte.WithLoadTTL[string, string](30*time.Millisecond))
+	defer cache.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if val, err := cache.Get(ctx, "a"); err != nil || val != "value-a" {
+			t.Errorf("Expected 'value-a', got %q (err: %v)", val, err)
+		}
+	}
+	if n := loads.Load(); n != 1 {
+		t.Errorf("Expected a single load, got %d", n)
+	}
+
+	// Loader errors are propagated and not cached.
+	for i := 0; i < 2; i++ {
+		if _, err := cache.Get(ctx, "missing"); !errors.Is(err, goutte.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+	}
+	if n := loads.Load(); n != 3 {
+		t.Errorf("Expected failed loads to be retried, got %d loads", n)
+	}
+
+	// Loaded entries expire with the load TTL.
+	time.Sleep(50 * time.Millisecond)
+	cache.Get(ctx, "a")
+	if n := loads.Load(); n != 4 {
+		t.Errorf("Expected 'a' to be reloaded after its TTL, got %d loads", n)
+	}
+}
+
+func TestLoadingCacheErrorTTL(t *testing.T) {
+	var loads atomic.Int32
+	loader := func(ctx context.Context, key int) (int, error) {
+		loads.Add(1)
+		return 0, fmt.Errorf("backend down")
+	}
+	cache := goutte.NewLoadingCache(goutte.NewCache[int, int](2), loader,
+		goutte.WithErrorTTL[int, int](30*time.Millisecond, 8))
+	defer cache.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := cache.Get(ctx, 1); err == nil {
+			t.Error("Expected the loader error")
+		}
+	}
+	if n := loads.Load(); n != 1 {
+		t.Errorf("Expected the error to be cached, got %d loads", n)
+	}
+
+	// A value stored directly is served over the cached error.
+	cache.Set(1, 10)
+	if val, err := cache.Get(ctx, 1); err != nil || val != 10 {
+		t.Errorf("Expected 10, got %d (err: %v)", val, err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	cache.Get(ctx, 2)
+	cache.Get(ctx, 2)
+	if n := loads.Load(); n != 2 {
+		t.Errorf("Expected one more load for key 2, got %d loads", n)
+	}
+}
+
+func TestLoadingCacheWithTTLFunc(t *testing.T) {
+	loader := func(ctx context.Context, key int) (int, error) { return key, nil }
+	cache := goutte.NewLoadingCache(goutte.NewCache[int, int](4), loader,
+		goutte.WithLoadTTLFunc(func(key, value int) time.Duration { return time.Duration(value) * time.Minute }))
+	defer cache.Close()
+
+	cache.Get(context.Background(), 0)
+	cache.Get(context.Background(), 5)
+	for _, e := range cache.Snapshot() {
+		if (e.Key == 0) != e.Expiration.IsZero() {
+			t.Errorf("Unexpected expiration for key %d: %v", e.Key, e.Expiration)
+		}
+	}
+}
+
+func TestLoadingCacheRefreshAfter(t *testing.T) {
+	var version atomic.Int32
+	loader := func(ctx context.Context, key string) (int, error) {
+		return int(version.Add(1)), nil
+	}
+	cache := goutte.NewLoadingCache(goutte.NewCache[string, int](2), loader,
+		goutte.WithLoadTTL[string, int](time.Second),
+		goutte.WithRefreshAfter[string, int](20*time.Millisecond))
+	defer cache.Close()
+
+	ctx := context.Background()
+	if val, _ := cache.Get(ctx, "a"); val != 1 {
+		t.Errorf("Expected the first load, got %d", val)
+	}
+	if val, _ := cache.Get(ctx, "a"); val != 1 {
+		t.Errorf("Expected no refresh before the delay, got %d", val)
+	}
+
+	// The read past the delay still returns the current value and triggers a background refresh.
+	time.Sleep(40 * time.Millisecond)
+	if val, _ := cache.Get(ctx, "a"); val != 1 {
+		t.Errorf("Expected the current value while refreshing, got %d", val)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		if val, _ := cache.Peek("a"); val == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected 'a' to be refreshed in the background")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if n := version.Load(); n != 2 {
+		t.Errorf("Expected a single refresh, got %d loads", n)
+	}
+}
+
+type loadSpanKey struct{}
+
+// Records the operations reported to a LoadTracer.
+type recordingTracer struct {
+	mu  sync.Mutex
+	ops []string
+}
+
+func (r *recordingTracer) record(op string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ops = append(r.ops, op)
+}
+
+func (r *recordingTracer) StartGet(ctx context.Context, key string) (context.Context, func(bool, error)) {
+	return ctx, func(hit bool, err error) {
+		r.record(fmt.Sprintf("get %s hit=%v err=%v", key, hit, err))
+	}
+}
+
+func (r *recordingTracer) StartLoad(ctx context.Context, key string) (context.Context, func(error)) {
+	return context.WithValue(ctx, loadSpanKey{}, "load"), func(err error) {
+		r.record(fmt.Sprintf("load %s err=%v", key, err))
+	}
+}
+
+func TestLoadingCacheTracer(t *testing.T) {
+	tracer := &recordingTracer{}
+	loader := func(ctx context.Context, key string) (string, error) {
+		if ctx.Value(loadSpanKey{}) != "load" {
+			t.Error("Expected the loader to run with the context returned by StartLoad")
+		}
+		if key == "missing" {
+			return "", goutte.ErrNotFound
+		}
+		return key, nil
+	}
+	cache := goutte.NewLoadingCache(goutte.NewCache[string, string](2), loader,
+		goutte.WithLoadTracer[string, string](tracer))
+	defer cache.Close()
+
+	ctx := context.Background()
+	cache.Get(ctx, "a")
+	cache.Get(ctx, "a")
+	cache.Get(ctx, "missing")
+
+	want := []string{
+		"load a err=<nil>",
+		"get a hit=false err=<nil>",
+		"get a hit=true err=<nil>",
+		"load missing err=goutte: key not found",
+		"get missing hit=false err=goutte: key not found",
+	}
+	if !slices.Equal(tracer.ops, want) {
+		t.Errorf("Expected operations %q, got %q", want, tracer.ops)
+	}
+}
+
+func TestLoadingCacheRefreshPanic(t *testing.T) {
+	var loads atomic.Int32
+	loader := func(ctx context.Context, key string) (int, error) {
+		n := loads.Add(1)
+		if n == 2 {
+			panic("backend exploded")
+		}
+		return int(n), nil
+	}
+	tracer := &recordingTracer{}
+	clock := goutte.NewManualClock(time.Unix(0, 0))
+	cache := goutte.NewLoadingCache(goutte.NewCache(2, goutte.WithClock[string, int](clock)), loader,
+		goutte.WithRefreshAfter[string, int](time.Second),
+		goutte.WithLoadTracer[string, int](tracer))
+	defer cache.Close()
+	ctx := context.Background()
+	cache.Get(ctx, "a")
+
+	// The panicking refresh does not crash the program, and the current value is kept.
+	clock.Advance(2 * time.Second)
+	if val, err := cache.Get(ctx, "a"); err != nil || val != 1 {
+		t.Errorf("Expected the current value while refreshing, got %d (error: %v)", val, err)
+	}
+	waitRefresh := func() {
+		t.Helper()
+		for deadline := time.Now().Add(5 * time.Second); cache.Stats().Flights > 0; time.Sleep(time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatal("Expected the refresh to complete")
+			}
+		}
+	}
+	waitRefresh()
+	if val, _ := cache.Peek("a"); val != 1 {
+		t.Errorf("Expected the failed refresh to keep the current value, got %d", val)
+	}
+	tracer.mu.Lock()
+	if last := tracer.ops[len(tracer.ops)-1]; last != "load a err=goutte: loading key a panicked" {
+		t.Errorf("Expected the refresh to be traced as failed, got %q", last)
+	}
+	tracer.mu.Unlock()
+
+	// The refresh is retried once the delay elapses again.
+	clock.Advance(2 * time.Second)
+	cache.Get(ctx, "a")
+	waitRefresh()
+	if val, _ := cache.Peek("a"); val != 3 {
+		t.Errorf("Expected the retried refresh to store 3, got %d", val)
+	}
+}
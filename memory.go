@@ -0,0 +1,122 @@
+package goutte
+
+import (
+	"container/list"
+	"reflect"
+	"unsafe"
+)
+
+// Bounds the approximate memory used by the entries to maxBytes, evicting least recently used entries
+// to stay under it, as WithWeigher does with a weigher estimating the size of each entry: its key and
+// value, including the strings, slices, maps and pointers they reference, and the bookkeeping of the
+// cache. The estimate does not account for allocator rounding or memory shared with other entries, so
+// leave some headroom. Stats.Weight reports the current estimate in bytes.
+//
+// Estimating walks the key and value on every write; for large or deeply nested values, prefer
+// WithWeigher with a cheaper weigher, or SetWithCost.
+func WithMaxMemory[K comparable, V any](maxBytes int64) Option[K, V] {
+	return WithWeigher(maxBytes, entrySize[K, V])
+}
+
+// Approximate bytes used by the entry of key and value: the entry itself, its list element and map
+// slot, and the memory referenced by the key and value.
+func entrySize[K comparable, V any](key K, value V) int64 {
+	var e entry[K, V]
+	var ele list.Element
+	size := int64(unsafe.Sizeof(e)) + int64(unsafe.Sizeof(ele)) + int64(unsafe.Sizeof(key)) + mapSlotOverhead
+	return size + referencedSize(key) + referencedSize(value)
+}
+
+// Bytes per map slot beyond its key, for the element pointer and the bucket metadata.
+const mapSlotOverhead = int64(unsafe.Sizeof(uintptr(0))) + 2
+
+// Returns the bytes referenced by v, not counting v itself, with fast paths for common types.
+func referencedSize[T any](v T) int64 {
+	switch v := any(v).(type) {
+	case string:
+		return int64(len(v))
+	case []byte:
+		return int64(cap(v))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr,
+		float32, float64, complex64, complex128, bool:
+		return 0
+	}
+	rv := reflect.ValueOf(&v).Elem()
+	return (&sizer{seen: make(map[uintptr]bool)}).indirect(rv)
+}
+
+// Walks values to sum the memory they reference, counting memory reachable twice once.
+type sizer struct {
+	seen map[uintptr]bool
+}
+
+// Returns the bytes referenced by v, not counting the inline size of v itself.
+func (s *sizer) indirect(v reflect.Value) int64 {
+	switch v.Kind() {
+	case reflect.String:
+		return int64(v.Len())
+	case reflect.Pointer:
+		if v.IsNil() || !s.visit(v.Pointer()) {
+			return 0
+		}
+		return s.direct(v.Elem())
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		elem := v.Elem()
+		// Non-pointer dynamic values are boxed in a separate allocation.
+		if elem.Kind() == reflect.Pointer {
+			return s.indirect(elem)
+		}
+		return s.direct(elem)
+	case reflect.Slice:
+		if v.IsNil() || !s.visit(v.Pointer()) {
+			return 0
+		}
+		size := int64(v.Cap()) * int64(v.Type().Elem().Size())
+		for i := 0; i < v.Len(); i++ {
+			size += s.indirect(v.Index(i))
+		}
+		return size
+	case reflect.Array:
+		var size int64
+		for i := 0; i < v.Len(); i++ {
+			size += s.indirect(v.Index(i))
+		}
+		return size
+	case reflect.Struct:
+		var size int64
+		for i := 0; i < v.NumField(); i++ {
+			size += s.indirect(v.Field(i))
+		}
+		return size
+	case reflect.Map:
+		if v.IsNil() || !s.visit(v.Pointer()) {
+			return 0
+		}
+		slot := int64(v.Type().Key().Size()+v.Type().Elem().Size()) + 1 // 1 byte of control metadata
+		size := int64(v.Len()) * slot
+		iter := v.MapRange()
+		for iter.Next() {
+			size += s.indirect(iter.Key()) + s.indirect(iter.Value())
+		}
+		return size
+	}
+	// Numbers, booleans, channels, functions and unsafe pointers are counted inline only.
+	return 0
+}
+
+// Returns the inline and referenced bytes of v.
+func (s *sizer) direct(v reflect.Value) int64 {
+	return int64(v.Type().Size()) + s.indirect(v)
+}
+
+// Reports whether the memory at p is visited for the first time.
+func (s *sizer) visit(p uintptr) bool {
+	if s.seen[p] {
+		return false
+	}
+	s.seen[p] = true
+	return true
+}
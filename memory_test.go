@@ -0,0 +1,54 @@
+package goutte_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheMaxMemory(t *testing.T) {
+	cache := goutte.NewCache(1000, goutte.WithMaxMemory[string, string](4096))
+	defer cache.Close()
+
+	cache.Set("small", "x")
+	small := cache.Stats().Weight
+	if small <= 0 || small > 512 {
+		t.Errorf("Expected a small entry to weigh its bookkeeping only, got %d bytes", small)
+	}
+	cache.Set("big", strings.Repeat("x", 1000))
+	if w := cache.Stats().Weight - 2*small; w < 990 || w > 1010 {
+		t.Errorf("Expected a 1000-byte value to weigh about 1000 bytes more, got %d", w)
+	}
+
+	for i := 0; i < 10; i++ {
+		cache.Set(strings.Repeat("k", i+1), strings.Repeat("v", 1000))
+	}
+	if w := cache.Stats().Weight; w > 4096 {
+		t.Errorf("Expected the cache to stay under 4096 bytes, got %d", w)
+	}
+	if n := cache.Len(); n < 2 || n > 4 {
+		t.Errorf("Expected 2 to 4 entries of 1000 bytes to fit, got %d", n)
+	}
+}
+
+type node struct {
+	name     string
+	children []*node
+	attrs    map[string]any
+	parent   *node
+}
+
+func TestCacheMaxMemoryNested(t *testing.T) {
+	cache := goutte.NewCache(10, goutte.WithMaxMemory[int, *node](1<<20))
+	defer cache.Close()
+
+	root := &node{name: strings.Repeat("r", 100), attrs: map[string]any{"tag": strings.Repeat("t", 200)}}
+	root.children = []*node{{name: strings.Repeat("c", 300), parent: root}}
+	cache.Set(1, root)
+
+	// The cycle between root and its child is counted once.
+	if w := cache.Stats().Weight; w < 600 || w > 2000 {
+		t.Errorf("Expected the tree to weigh between 600 and 2000 bytes, got %d", w)
+	}
+}
@@ -0,0 +1,239 @@
+package goutte
+
+import (
+	"sync"
+	"time"
+)
+
+// Progress of a migration started by Migrate, as reported by Stats.
+type MigrationStats struct {
+	Copied int  // entries copied to the target so far
+	Total  int  // live entries in the source when the migration started
+	Done   bool // whether every entry has been copied; writes are still mirrored until Stop
+}
+
+// Live migration of a cache's contents to another cache; see Migrate.
+type Migration[K comparable, V any] struct {
+	c        *Cache[K, V]
+	st       *migrationState[K, V]
+	done     chan struct{}
+	stop     chan struct{}
+	exited   chan struct{} // closed when the background goroutine returns
+	stopOnce sync.Once
+}
+
+type migrationState[K comparable, V any] struct {
+	target *Cache[K, V]
+	keys   []K // keys to copy, least recently used first
+	next   int // index in keys of the next key to copy
+	ops    []migrationOp[K, V]
+	signal chan struct{}
+	stats  MigrationStats
+}
+
+// Write to replay on the target, in source lock order. Ops are queued with a key only, and resolved
+// against the source entry when they are drained, so the target gets its latest state.
+type migrationOp[K comparable, V any] struct {
+	key        K
+	value      V
+	expiration time.Time
+	ttl        time.Duration // TTL renewed by Touch, and by hits if sliding
+	sliding    bool
+	cost       int64 // explicit cost given to SetWithCost, or -1 to weigh the value
+	del        bool  // delete key instead of writing it
+	dump       bool  // clear the target
+}
+
+// Incrementally copies the live entries of the cache to target in the background, at about rate
+// entries per second (all at once if rate is not positive), without a cold start for target. Entries
+// keep their absolute expiration, TTL settings and explicit cost, and are copied from least to most
+// recently used, so target ends up with a similar recency order. Meanwhile, writes (including field
+// and counter updates, expiration changes, sliding TTL and hit-streak renewals by reads, and
+// Resurrect), deletes (including SoftDelete) and Dump on the cache are mirrored to target in the order
+// they happen, so target converges to the same contents.
+//
+// Once Done is closed, callers can switch to target and call Stop. Only one migration per source
+// cache can run at a time; target must not migrate back to the cache. Closing the cache ends the
+// migration, after applying the writes mirrored so far, and closes Done.
+func (c *Cache[K, V]) Migrate(target *Cache[K, V], rate int) *Migration[K, V] {
+	if target == c {
+		panic("cannot migrate a cache to itself")
+	}
+
+	c.lock()
+	if c.migration != nil {
+		c.unlock()
+		panic("a migration is already in progress")
+	}
+	st := &migrationState[K, V]{target: target, signal: make(chan struct{}, 1)}
+	c.forEachLiveLocked(func(ent *entry[K, V]) {
+		st.keys = append(st.keys, ent.key)
+	})
+	st.stats.Total = len(st.keys)
+	c.migration = st
+	c.unlock()
+
+	m := &Migration[K, V]{c: c, st: st, done: make(chan struct{}), stop: make(chan struct{}), exited: make(chan struct{})}
+	go m.run(rate)
+	return m
+}
+
+// Closed once every entry present when the migration started has been copied, or once the source
+// cache is closed.
+func (m *Migration[K, V]) Done() <-chan struct{} {
+	return m.done
+}
+
+// Stops copying and mirroring. Writes mirrored before Stop are applied to the target before it returns.
+func (m *Migration[K, V]) Stop() {
+	m.stopOnce.Do(func() {
+		m.c.lock()
+		if m.c.migration == m.st {
+			m.c.migration = nil
+		}
+		m.c.unlock()
+		close(m.stop)
+	})
+	<-m.exited
+}
+
+func (m *Migration[K, V]) run(rate int) {
+	defer close(m.exited)
+	const tick = 100 * time.Millisecond
+	batch := 0 // unlimited
+	if rate > 0 {
+		batch = max(1, rate/int(time.Second/tick))
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	c, st := m.c, m.st
+	doneClosed := false
+	for {
+		c.lock()
+		for n := 0; st.next < len(st.keys) && (batch == 0 || n < batch); n++ {
+			key := st.keys[st.next]
+			st.next++
+			if c.lookupLocked(key) != nil {
+				st.ops = append(st.ops, migrationOp[K, V]{key: key})
+				st.stats.Copied++
+			}
+		}
+		ops := c.drainMigrationLocked(st)
+		finished := st.next == len(st.keys) && !st.stats.Done
+		if finished {
+			st.stats.Done = true
+			st.keys = nil
+		}
+		c.unlock()
+
+		st.apply(ops)
+		if finished {
+			close(m.done)
+			doneClosed = true
+		}
+
+		select {
+		case <-ticker.C:
+		case <-st.signal:
+		case <-m.stop:
+			c.lock()
+			ops := c.drainMigrationLocked(st)
+			c.unlock()
+			st.apply(ops)
+			return
+		case <-c.done:
+			// The source is closed: stop mirroring into a queue nobody drains.
+			c.lock()
+			ops := c.drainMigrationLocked(st)
+			if c.migration == st {
+				c.migration = nil
+			}
+			c.unlock()
+			st.apply(ops)
+			if !doneClosed {
+				close(m.done)
+			}
+			return
+		}
+	}
+}
+
+// Takes the queued ops of st, resolved against the current entries.
+func (c *Cache[K, V]) drainMigrationLocked(st *migrationState[K, V]) []migrationOp[K, V] {
+	ops := st.ops
+	st.ops = nil
+	now := c.clock.Now()
+	for i, op := range ops {
+		if op.dump {
+			continue
+		}
+		ele, ok := c.cache[op.key]
+		if !ok {
+			ops[i].del = true
+			continue
+		}
+		ent := ele.Value.(*entry[K, V])
+		if ent.tombstone || (!ent.expiration.IsZero() && now.After(ent.expiration)) {
+			ops[i].del = true
+			continue
+		}
+		cost := int64(-1)
+		if ent.costed {
+			cost = ent.weight
+		}
+		ops[i] = migrationOp[K, V]{
+			key:        op.key,
+			value:      ent.value,
+			expiration: ent.expiration,
+			ttl:        ent.ttl,
+			sliding:    ent.sliding,
+			cost:       cost,
+		}
+	}
+	return ops
+}
+
+func (st *migrationState[K, V]) apply(ops []migrationOp[K, V]) {
+	if len(ops) == 0 {
+		return
+	}
+	t := st.target
+	t.lock()
+	defer t.unlock()
+
+	now := t.clock.Now()
+	for _, op := range ops {
+		switch {
+		case op.dump:
+			t.dumpLocked()
+		case op.del:
+			t.deleteLocked(op.key)
+		case op.expiration.IsZero() || now.Before(op.expiration):
+			ent := t.setCostLocked(op.key, op.value, op.expiration, op.cost)
+			ent.ttl, ent.sliding = op.ttl, op.sliding
+		default:
+			// Expired on the target's clock.
+			t.deleteLocked(op.key)
+		}
+	}
+}
+
+// Queues a write or delete of key for replay on the migration target. A migration must be running.
+func (c *Cache[K, V]) mirrorLocked(key K) {
+	c.queueMigrationLocked(migrationOp[K, V]{key: key})
+}
+
+// Queues a Dump for replay on the migration target. A migration must be running.
+func (c *Cache[K, V]) mirrorDumpLocked() {
+	c.queueMigrationLocked(migrationOp[K, V]{dump: true})
+}
+
+func (c *Cache[K, V]) queueMigrationLocked(op migrationOp[K, V]) {
+	st := c.migration
+	st.ops = append(st.ops, op)
+	select {
+	case st.signal <- struct{}{}:
+	default:
+	}
+}
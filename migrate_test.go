@@ -0,0 +1,202 @@
+package goutte_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheMigrate(t *testing.T) {
+	source := goutte.NewCache[int, int](100)
+	defer source.Close()
+	target := goutte.NewCache[int, int](100, goutte.WithPromotion[int, int](goutte.PromoteColdHalf()))
+	defer target.Close()
+
+	for i := 0; i < 50; i++ {
+		source.Set(i, i)
+	}
+	source.SetWithTTL(50, 50, time.Minute)
+
+	// Copy 100 entries per second, so the migration takes about half a second.
+	m := source.Migrate(target, 100)
+	defer m.Stop()
+
+	// Writes during the migration are mirrored in order.
+	source.Set(0, -1)
+	source.Delete(1)
+	source.Set(100, 100)
+
+	select {
+	case <-m.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Migration did not complete")
+	}
+	if stats := source.Stats().Migration; !stats.Done || stats.Total != 51 || stats.Copied != 50 {
+		t.Errorf("Expected 50 of 51 entries copied, got %+v", stats)
+	}
+
+	source.Set(101, 101)
+	m.Stop()
+
+	if got, want := target.Items(), source.Items(); len(got) != len(want) {
+		t.Fatalf("Expected target to hold %d entries, got %d", len(want), len(got))
+	} else {
+		for k, v := range want {
+			if got[k] != v {
+				t.Errorf("Expected key %d to be %d in target, got %d", k, v, got[k])
+			}
+		}
+	}
+	if entries := target.Snapshot(); entries[len(entries)-1].Key != 101 {
+		t.Errorf("Expected the last mirrored write to be the most recent, got %v", slices.Collect(target.Keys()))
+	}
+	for _, e := range target.Snapshot() {
+		if e.Key == 50 && e.Expiration.IsZero() {
+			t.Error("Expected key 50 to keep its expiration")
+		}
+	}
+
+	// Nothing is mirrored after Stop.
+	source.Set(102, 102)
+	time.Sleep(150 * time.Millisecond)
+	if target.Contains(102) {
+		t.Error("Expected writes after Stop not to be mirrored")
+	}
+}
+
+func TestCacheMigrateMirrorsInPlaceUpdates(t *testing.T) {
+	source := goutte.NewCache[string, map[string]int](10)
+	defer source.Close()
+	target := goutte.NewCache[string, map[string]int](10)
+	defer target.Close()
+	source.Set("session", map[string]int{"a": 1})
+	goutte.SetField(source, "user", "age", 30)
+
+	m := source.Migrate(target, 0)
+	<-m.Done()
+
+	source.SoftDelete("session", time.Minute)
+	source.Resurrect("session")
+	goutte.SetField(source, "user", "score", 7)
+	goutte.DeleteField(source, "user", "age")
+	m.Stop()
+
+	if v, ok := target.Get("session"); !ok || v["a"] != 1 {
+		t.Errorf("Expected the resurrected key to be mirrored, got %v (found: %v)", v, ok)
+	}
+	if v, ok := target.Get("user"); !ok || len(v) != 1 || v["score"] != 7 {
+		t.Errorf("Expected the field updates to be mirrored, got %v (found: %v)", v, ok)
+	}
+}
+
+func TestCacheMigrateMirrorsTouch(t *testing.T) {
+	source := goutte.NewCache[string, int](10)
+	defer source.Close()
+	target := goutte.NewCache[string, int](10)
+	defer target.Close()
+	source.SetWithTTL("touched", 1, time.Minute)
+	source.SetWithTTL("extended", 2, time.Minute)
+
+	m := source.Migrate(target, 0)
+	<-m.Done()
+
+	source.Expire("touched", time.Hour) // the TTL that Touch renews
+	source.Touch("touched")
+	source.TouchWithTTL("extended", time.Hour)
+	m.Stop()
+
+	for _, key := range []string{"touched", "extended"} {
+		_, want, _ := source.GetWithExpiration(key)
+		if _, got, ok := target.GetWithExpiration(key); !ok || !got.Equal(want) {
+			t.Errorf("Expected key %q to expire at %v in target, got %v (found: %v)", key, want, got, ok)
+		}
+	}
+}
+
+func TestCacheMigrateMirrorsGroupDeadline(t *testing.T) {
+	source := goutte.NewCache[string, int](10)
+	defer source.Close()
+	target := goutte.NewCache[string, int](10)
+	defer target.Close()
+	source.Set("a", 1)
+	source.Set("b", 2)
+
+	m := source.Migrate(target, 0)
+	<-m.Done()
+
+	at := time.Now().Add(time.Hour)
+	source.SetGroupDeadline([]string{"a", "b"}, at)
+	m.Stop()
+
+	for _, key := range []string{"a", "b"} {
+		if _, exp, ok := target.GetWithExpiration(key); !ok || !exp.Equal(at) {
+			t.Errorf("Expected key %q to expire at %v in target, got %v (found: %v)", key, at, exp, ok)
+		}
+	}
+}
+
+func TestCacheMigrateMirrorsSlidingRenewals(t *testing.T) {
+	clock := goutte.NewManualClock(time.Unix(0, 0))
+	source := goutte.NewCache(10, goutte.WithClock[string, int](clock))
+	defer source.Close()
+	target := goutte.NewCache(10, goutte.WithClock[string, int](clock))
+	defer target.Close()
+	source.SetWithSlidingTTL("a", 1, 10*time.Second)
+
+	m := source.Migrate(target, 0)
+	<-m.Done()
+
+	// Reads keep the entry alive in the source, and must do so in the target too.
+	clock.Advance(6 * time.Second)
+	source.Get("a")
+	m.Stop()
+	clock.Advance(6 * time.Second)
+	if _, ok := target.Get("a"); !ok {
+		t.Fatal("Expected the sliding renewal to be mirrored")
+	}
+
+	// The target renews the entry on its own reads once it takes over.
+	clock.Advance(8 * time.Second)
+	if _, ok := target.Get("a"); !ok {
+		t.Error("Expected the target to keep the sliding TTL")
+	}
+}
+
+func TestCacheMigrateKeepsCost(t *testing.T) {
+	source := goutte.NewCache(10, goutte.WithWeigher[string, string](100, nil))
+	defer source.Close()
+	target := goutte.NewCache(10, goutte.WithWeigher[string, string](100, nil))
+	defer target.Close()
+	source.SetWithCost("a", "x", 40, 0)
+
+	m := source.Migrate(target, 0)
+	<-m.Done()
+	source.SetWithCost("b", "y", 30, 0)
+	source.Set("c", "z")
+	m.Stop()
+
+	if w := target.Stats().Weight; w != 71 {
+		t.Errorf("Expected the costs to be copied for a total weight of 71, got %d", w)
+	}
+}
+
+func TestCacheMigrateDoneOnClose(t *testing.T) {
+	source := goutte.NewCache[int, int](100)
+	target := goutte.NewCache[int, int](100)
+	defer target.Close()
+	for i := 0; i < 100; i++ {
+		source.Set(i, i)
+	}
+
+	// At one entry per second, the copy would take minutes.
+	m := source.Migrate(target, 1)
+	defer m.Stop()
+	source.Close()
+	select {
+	case <-m.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected Done to be closed when the source is closed")
+	}
+}
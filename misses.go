@@ -0,0 +1,59 @@
+package goutte
+
+import "time"
+
+// Read miss recorded by WithRecentMisses.
+type Miss[K comparable] struct {
+	Key  K
+	Time time.Time
+}
+
+// Fixed-size ring buffer of the most recent misses.
+type missLog[K comparable] struct {
+	buf    []Miss[K]
+	next   int // index of the slot to overwrite next
+	full   bool
+	redact func(K) K
+}
+
+func (m *missLog[K]) record(key K, now time.Time) {
+	if m.redact != nil {
+		key = m.redact(key)
+	}
+	m.buf[m.next] = Miss[K]{Key: key, Time: now}
+	m.next++
+	if m.next == len(m.buf) {
+		m.next = 0
+		m.full = true
+	}
+}
+
+// Keeps the n most recent read misses with their timestamps, exposed through RecentMisses,
+// to investigate which traffic the cache is failing to serve. If redact is non-nil, keys are passed
+// through it before being stored, e.g. to keep only a hash of privacy-sensitive keys.
+func WithRecentMisses[K comparable, V any](n int, redact func(K) K) Option[K, V] {
+	if n <= 0 {
+		panic("miss log size must be greater than zero")
+	}
+	return func(c *Cache[K, V]) {
+		c.missLog = &missLog[K]{buf: make([]Miss[K], n), redact: redact}
+	}
+}
+
+// Returns the recorded misses, oldest first.
+// It returns nil if the miss log is not enabled with WithRecentMisses.
+func (c *Cache[K, V]) RecentMisses() []Miss[K] {
+	c.lock()
+	defer c.unlock()
+
+	if c.missLog == nil {
+		return nil
+	}
+	m := c.missLog
+	if !m.full {
+		return append([]Miss[K](nil), m.buf[:m.next]...)
+	}
+	out := make([]Miss[K], 0, len(m.buf))
+	out = append(out, m.buf[m.next:]...)
+	return append(out, m.buf[:m.next]...)
+}
@@ -0,0 +1,38 @@
+package goutte_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheRecentMisses(t *testing.T) {
+	cache := goutte.NewCache[string, int](2, goutte.WithRecentMisses[string, int](2, nil))
+	defer cache.Close()
+	cache.Set("a", 1)
+
+	cache.Get("x")
+	cache.Get("a")
+	cache.Get("y")
+	cache.Get("z")
+
+	misses := cache.RecentMisses()
+	if len(misses) != 2 || misses[0].Key != "y" || misses[1].Key != "z" {
+		t.Fatalf("Expected the two most recent misses y, z, got %+v", misses)
+	}
+	if misses[0].Time.After(misses[1].Time) {
+		t.Errorf("Expected misses in chronological order, got %+v", misses)
+	}
+}
+
+func TestCacheRecentMissesRedacted(t *testing.T) {
+	redact := func(k string) string { return strings.Repeat("*", len(k)) }
+	cache := goutte.NewCache[string, int](2, goutte.WithRecentMisses[string, int](4, redact))
+	defer cache.Close()
+
+	cache.Get("secret")
+	if misses := cache.RecentMisses(); len(misses) != 1 || misses[0].Key != "******" {
+		t.Errorf("Expected a redacted miss, got %+v", misses)
+	}
+}
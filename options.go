@@ -0,0 +1,90 @@
+package goutte
+
+import "time"
+
+// Configures optional cache behavior at construction time; see NewCache.
+type Option[K comparable, V any] func(*Cache[K, V])
+
+// Records the approximate LRU position at which hits occur, exposed through HitRanks.
+// This shows whether a smaller cache would serve nearly as many hits.
+func WithRankHistogram[K comparable, V any]() Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.ranks = newRankTracker(c.capacity)
+	}
+}
+
+// Consults canEvict before evicting an entry for capacity, skipping entries it vetoes (for example,
+// values representing in-flight work) in favor of the next least recently used one.
+// To avoid livelock when every entry is vetoed, at most maxAttempts candidates are consulted per
+// eviction (this bound also applies to WithMinResidency); if all of them are skipped, the least
+// recently used entry is evicted anyway.
+// Expired and soft-deleted entries are never vetoed. The hook runs with the cache lock held and
+// must not call back into the cache.
+func WithEvictionVeto[K comparable, V any](canEvict func(key K, value V) bool, maxAttempts int) Option[K, V] {
+	if maxAttempts <= 0 {
+		panic("eviction veto attempts must be greater than zero")
+	}
+	return func(c *Cache[K, V]) {
+		c.canEvict = canEvict
+		c.evictAttempts = maxAttempts
+	}
+}
+
+// Protects newly inserted entries from capacity eviction for the given duration, so values loaded
+// during a scan get a chance to be reused before being pushed out. Updating an existing key does not
+// restart its window, and expiration and deletion are unaffected.
+// Eviction skips protected entries in favor of older ones, examining a bounded number of candidates
+// (32, or maxAttempts from WithEvictionVeto); if all of them are protected, the least recently used
+// entry is evicted anyway so the cache never exceeds its capacity.
+func WithMinResidency[K comparable, V any](d time.Duration) Option[K, V] {
+	if d <= 0 {
+		panic("minimum residency must be greater than zero")
+	}
+	return func(c *Cache[K, V]) {
+		c.minResidency = d
+	}
+}
+
+// Applies ttl to entries written with Set and other writes that take no TTL, such as SetField,
+// GetOrCompute and loads without WithLoadTTL. SetWithTTL still overrides it.
+func WithDefaultTTL[K comparable, V any](ttl time.Duration) Option[K, V] {
+	if ttl <= 0 {
+		panic("default TTL must be greater than zero")
+	}
+	return func(c *Cache[K, V]) {
+		c.defaultTTL = ttl
+	}
+}
+
+// Makes the TTL of every entry written with a TTL (including the default TTL) sliding: each hit
+// renews its expiration to the TTL from now, so entries expire after being idle for their TTL
+// rather than after being written. Single entries can use sliding TTLs with SetWithSlidingTTL.
+func WithExpireAfterAccess[K comparable, V any]() Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.expireAfterAccess = true
+	}
+}
+
+// Registers a callback invoked with the key and value of every entry evicted to respect the capacity,
+// e.g. to release resources or write the value back to a slower store. Expiration and deletion do not
+// trigger it. It is a shorthand for WithOnRemoval keeping only EvictionCapacity; the two options
+// replace each other.
+func WithOnEvict[K comparable, V any](fn func(key K, value V)) Option[K, V] {
+	return WithOnRemoval(func(key K, value V, reason EvictionReason) {
+		if reason == EvictionCapacity {
+			fn(key, value)
+		}
+	})
+}
+
+// Registers a callback invoked with the key, value and reason of every entry leaving the cache:
+// capacity evictions, expirations, deletions, overwrites and Dump. Expired entries are reported when
+// they are actually removed, either by the expiration goroutine or by the operation that finds them.
+// The callback runs in the goroutine whose operation caused the removal, after the cache lock has
+// been released, so it may call back into the cache; removals by DeleteMany are delivered by a worker
+// goroutine instead.
+func WithOnRemoval[K comparable, V any](fn func(key K, value V, reason EvictionReason)) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.onRemoval = fn
+	}
+}
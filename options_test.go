@@ -0,0 +1,147 @@
+package goutte_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheEvictionVeto(t *testing.T) {
+	inFlight := map[string]bool{"a": true}
+	canEvict := func(k string, _ int) bool { return !inFlight[k] }
+	cache := goutte.NewCache[string, int](2, goutte.WithEvictionVeto(canEvict, 2))
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+
+	// "a" is the least recently used entry but is vetoed, so "b" is evicted instead.
+	cache.Set("c", 3)
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("Expected vetoed key 'a' to survive eviction")
+	}
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected key 'b' to be evicted in place of 'a'")
+	}
+}
+
+func TestCacheEvictionVetoForced(t *testing.T) {
+	canEvict := func(string, int) bool { return false }
+	cache := goutte.NewCache[string, int](2, goutte.WithEvictionVeto(canEvict, 2))
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+
+	// Every candidate is vetoed, so the least recently used entry is evicted anyway.
+	cache.Set("c", 3)
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected key 'a' to be force-evicted")
+	}
+	if _, ok := cache.Get("c"); !ok {
+		t.Error("Expected key 'c' to be present")
+	}
+}
+
+func TestCacheMinResidency(t *testing.T) {
+	cache := goutte.NewCache[string, int](2, goutte.WithMinResidency[string, int](50*time.Millisecond))
+	defer cache.Close()
+	cache.Set("old", 1)
+	time.Sleep(60 * time.Millisecond)
+
+	cache.Set("new", 2)
+	cache.Get("old") // "new" is now least recently used, but still protected
+
+	cache.Set("newer", 3)
+	if _, ok := cache.Get("new"); !ok {
+		t.Error("Expected protected key 'new' to survive eviction")
+	}
+	if _, ok := cache.Get("old"); ok {
+		t.Error("Expected unprotected key 'old' to be evicted instead")
+	}
+
+	// With every candidate protected, the least recently used entry is evicted anyway.
+	cache.Set("newest", 4)
+	if _, ok := cache.Get("newer"); ok {
+		t.Error("Expected key 'newer' to be force-evicted when every entry is protected")
+	}
+}
+
+func TestCacheOnEvict(t *testing.T) {
+	var evicted []string
+	var cache *goutte.Cache[string, int]
+	onEvict := func(k string, v int) {
+		evicted = append(evicted, k)
+		// Re-entrant calls must not deadlock.
+		cache.Get(k)
+	}
+	cache = goutte.NewCache[string, int](2, goutte.WithOnEvict(onEvict))
+	defer cache.Close()
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Delete("b")
+	cache.Set("c", 3)
+	cache.Set("d", 4)
+
+	cache.SetCapacity(1)
+
+	if len(evicted) != 2 || evicted[0] != "a" || evicted[1] != "c" {
+		t.Errorf("Expected evictions of a and c, got %v", evicted)
+	}
+}
+
+func TestCacheOnRemovalReasons(t *testing.T) {
+	var mu sync.Mutex
+	var removals []string
+	onRemoval := func(k string, v int, reason goutte.EvictionReason) {
+		mu.Lock()
+		defer mu.Unlock()
+		removals = append(removals, fmt.Sprintf("%s=%d:%v", k, v, reason))
+	}
+	cache := goutte.NewCache[string, int](2, goutte.WithOnRemoval(onRemoval))
+	defer cache.Close()
+
+	cache.Set("a", 1)
+	cache.Set("a", 2)
+	cache.Set("b", 3)
+	cache.Set("c", 4) // evicts a
+	cache.Delete("c")
+	cache.SetWithTTL("d", 5, 20*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	cache.Set("e", 6)
+	cache.Dump()
+
+	expected := []string{
+		"a=1:replaced",
+		"a=2:capacity",
+		"c=4:deleted",
+		"d=5:expired",
+		"b=3:cleared",
+		"e=6:cleared",
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if fmt.Sprint(removals) != fmt.Sprint(expected) {
+		t.Errorf("Expected removals %v, got %v", expected, removals)
+	}
+}
+
+func TestCacheDefaultTTL(t *testing.T) {
+	cache := goutte.NewCache[string, int](4, goutte.WithDefaultTTL[string, int](20*time.Millisecond))
+	defer cache.Close()
+
+	cache.Set("default", 1)
+	cache.SetWithTTL("override", 2, time.Minute)
+	cache.SetWithTTL("forever", 3, 0)
+	cache.GetOrCompute("computed", func() (int, error) { return 4, nil })
+
+	time.Sleep(50 * time.Millisecond)
+	if cache.Contains("default") || cache.Contains("computed") {
+		t.Error("Expected entries written without a TTL to expire with the default TTL")
+	}
+	if !cache.Contains("override") || !cache.Contains("forever") {
+		t.Error("Expected SetWithTTL to override the default TTL")
+	}
+}
@@ -0,0 +1,66 @@
+// Package otelgoutte traces goutte loading caches with OpenTelemetry.
+//
+// It lives in its own module so that goutte itself stays free of dependencies. Pass a Tracer to
+// goutte.WithLoadTracer:
+//
+//	cache := goutte.NewLoadingCache(goutte.NewCache[string, User](1000), loadUser,
+//		goutte.WithLoadTracer[string, User](otelgoutte.NewTracer[string](nil)))
+//
+// Every Get is recorded as a "goutte.Get" span with a cache.hit attribute, and every loader call,
+// including background refreshes, as a "goutte.Load" child span, so cache misses show up in
+// distributed traces and slow origins can be told apart from the cache itself. Keys are not recorded,
+// as they may be sensitive or of unbounded cardinality.
+package otelgoutte
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Instrumentation scope of the spans.
+const scope = "github.com/shellkah/goutte/otelgoutte"
+
+// Attribute set on Get spans, reporting whether the value was served from the cache.
+const HitKey = attribute.Key("cache.hit")
+
+// goutte.LoadTracer recording OpenTelemetry spans.
+type Tracer[K comparable] struct {
+	tracer trace.Tracer
+}
+
+// Returns a Tracer creating spans with provider, or with the global provider if it is nil.
+func NewTracer[K comparable](provider trace.TracerProvider) *Tracer[K] {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &Tracer[K]{tracer: provider.Tracer(scope)}
+}
+
+// Starts a "goutte.Get" span, ended with the cache.hit attribute and the error of the read.
+func (t *Tracer[K]) StartGet(ctx context.Context, key K) (context.Context, func(bool, error)) {
+	ctx, span := t.tracer.Start(ctx, "goutte.Get", trace.WithSpanKind(trace.SpanKindInternal))
+	return ctx, func(hit bool, err error) {
+		span.SetAttributes(HitKey.Bool(hit))
+		end(span, err)
+	}
+}
+
+// Starts a "goutte.Load" span, ended with the error of the loader.
+func (t *Tracer[K]) StartLoad(ctx context.Context, key K) (context.Context, func(error)) {
+	ctx, span := t.tracer.Start(ctx, "goutte.Load", trace.WithSpanKind(trace.SpanKindInternal))
+	return ctx, func(err error) {
+		end(span, err)
+	}
+}
+
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,66 @@
+package otelgoutte_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shellkah/goutte"
+	"github.com/shellkah/goutte/otelgoutte"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+
+	errDown := errors.New("backend down")
+	loader := func(ctx context.Context, key string) (int, error) {
+		if key == "down" {
+			return 0, errDown
+		}
+		return len(key), nil
+	}
+	cache := goutte.NewLoadingCache(goutte.NewCache[string, int](2), loader,
+		goutte.WithLoadTracer[string, int](otelgoutte.NewTracer[string](provider)))
+	defer cache.Close()
+
+	ctx := context.Background()
+	cache.Get(ctx, "a")
+	cache.Get(ctx, "a")
+	cache.Get(ctx, "down")
+
+	spans := exporter.GetSpans()
+	if len(spans) != 5 {
+		t.Fatalf("Expected 5 spans, got %d", len(spans))
+	}
+	hits := map[bool]int{}
+	for _, span := range spans {
+		switch span.Name {
+		case "goutte.Load":
+			// Spans are exported when they end, so a load precedes the Get it belongs to.
+		case "goutte.Get":
+			for _, attr := range span.Attributes {
+				if attr.Key == otelgoutte.HitKey {
+					hits[attr.Value.AsBool()]++
+				}
+			}
+		default:
+			t.Errorf("Unexpected span %q", span.Name)
+		}
+	}
+	if hits[true] != 1 || hits[false] != 2 {
+		t.Errorf("Expected 1 hit and 2 misses, got %v", hits)
+	}
+
+	load, get := spans[0], spans[1]
+	if load.Name != "goutte.Load" || load.Parent.SpanID() != get.SpanContext.SpanID() {
+		t.Errorf("Expected the load to be a child of the Get that missed, got %q", load.Name)
+	}
+	if failed := spans[3]; failed.Status.Code != codes.Error || failed.Status.Description != errDown.Error() {
+		t.Errorf("Expected the failed load to record its error, got %+v", failed.Status)
+	}
+}
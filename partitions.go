@@ -0,0 +1,70 @@
+package goutte
+
+// Hit, miss and eviction counts attributed to a key partition; see WithPartitions.
+type PartitionStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64 // capacity evictions
+}
+
+// Per-partition counters with bounded cardinality, keyed by the classifier output.
+type partitionTracker[K comparable] struct {
+	classify func(K) string
+	max      int
+	counts   map[string]*PartitionStats
+}
+
+// Returns the counters of the partition holding key, folding new partitions into OverflowLabel
+// once the limit is reached.
+func (pt *partitionTracker[K]) stats(key K) *PartitionStats {
+	name := pt.classify(key)
+	ps, ok := pt.counts[name]
+	if !ok {
+		// One slot is reserved for the overflow label.
+		if name != OverflowLabel && len(pt.counts) >= pt.max-1 {
+			name = OverflowLabel
+			ps = pt.counts[name]
+		}
+		if ps == nil {
+			ps = &PartitionStats{}
+			pt.counts[name] = ps
+		}
+	}
+	return ps
+}
+
+// Buckets hit, miss and eviction counters by the partition classify assigns to each key (e.g. a key
+// prefix or tenant), so a shared cache can be analyzed per logical partition. At most max partitions
+// are tracked, including OverflowLabel, under which partitions beyond the limit are counted.
+// classify is called with the cache lock held and must be cheap. The counters are read with PartitionStats.
+func WithPartitions[K comparable, V any](classify func(K) string, max int) Option[K, V] {
+	if classify == nil {
+		panic("partition classifier must not be nil")
+	}
+	if max < 2 {
+		panic("partition limit must be at least 2")
+	}
+	return func(c *Cache[K, V]) {
+		c.partitions = &partitionTracker[K]{
+			classify: classify,
+			max:      max,
+			counts:   make(map[string]*PartitionStats),
+		}
+	}
+}
+
+// Returns a copy of the per-partition counters, keyed by partition name.
+// It returns nil if partitions are not enabled with WithPartitions.
+func (c *Cache[K, V]) PartitionStats() map[string]PartitionStats {
+	c.lock()
+	defer c.unlock()
+
+	if c.partitions == nil {
+		return nil
+	}
+	stats := make(map[string]PartitionStats, len(c.partitions.counts))
+	for name, ps := range c.partitions.counts {
+		stats[name] = *ps
+	}
+	return stats
+}
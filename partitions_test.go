@@ -0,0 +1,57 @@
+package goutte_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/shellkah/goutte"
+)
+
+func tenantOf(key string) string {
+	tenant, _, _ := strings.Cut(key, ":")
+	return tenant
+}
+
+func TestCachePartitionStats(t *testing.T) {
+	cache := goutte.NewCache[string, int](2, goutte.WithPartitions[string, int](tenantOf, 8))
+	defer cache.Close()
+
+	cache.Set("acme:a", 1)
+	cache.Set("globex:a", 2)
+	cache.Get("acme:a")
+	cache.Get("acme:b")
+	cache.Set("globex:b", 3) // evicts globex:a
+	cache.Get("globex:a")
+
+	stats := cache.PartitionStats()
+	if s := stats["acme"]; s != (goutte.PartitionStats{Hits: 1, Misses: 1}) {
+		t.Errorf("Expected 1 hit and 1 miss for 'acme', got %+v", s)
+	}
+	if s := stats["globex"]; s != (goutte.PartitionStats{Misses: 1, Evictions: 1}) {
+		t.Errorf("Expected 1 miss and 1 eviction for 'globex', got %+v", s)
+	}
+}
+
+func TestCachePartitionStatsCardinality(t *testing.T) {
+	cache := goutte.NewCache[string, int](2, goutte.WithPartitions[string, int](tenantOf, 3))
+	defer cache.Close()
+
+	for i := 0; i < 10; i++ {
+		cache.Get(fmt.Sprintf("tenant-%d:key", i))
+	}
+
+	stats := cache.PartitionStats()
+	if len(stats) != 3 {
+		t.Fatalf("Expected partitions to be capped at 3, got %+v", stats)
+	}
+	if s := stats[goutte.OverflowLabel]; s.Misses != 8 {
+		t.Errorf("Expected 8 misses under the overflow label, got %+v", s)
+	}
+
+	plain := goutte.NewCache[string, int](1)
+	defer plain.Close()
+	if plain.PartitionStats() != nil {
+		t.Error("Expected nil partition stats when not enabled")
+	}
+}
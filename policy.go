@@ -0,0 +1,293 @@
+package goutte
+
+import (
+	"container/list"
+	"fmt"
+	"math/rand/v2"
+)
+
+type policyKind int
+
+const (
+	policyLRU policyKind = iota
+	policy2Q
+	policySLRU
+	policyClock
+	policyFIFO
+	policyRandom
+	policyMRU
+)
+
+// Eviction policy, deciding where entries are placed in the list and which one is evicted to respect
+// the capacity; see WithPolicy. The zero value is LRU.
+type Policy struct {
+	kind    policyKind
+	in, out float64 // 2Q queue sizes, as fractions of the capacity
+	protect float64 // SLRU protected segment size, as a fraction of the capacity
+}
+
+// Evicts the least recently used entry. This is the default.
+func PolicyLRU() Policy {
+	return Policy{}
+}
+
+// Scan-resistant 2Q policy (Johnson and Shasha). New keys enter a FIFO queue (A1in) holding about
+// in times the capacity, where hits do not move them. Keys evicted from A1in are remembered, without
+// their value, in a ghost queue (A1out) of about out times the capacity; a key written again while
+// remembered enters the main LRU queue (Am) directly. Entries are evicted from A1in while it exceeds
+// its share, and from Am otherwise, so a sequential scan only churns A1in. The paper suggests 0.25
+// and 0.5.
+func Policy2Q(in, out float64) Policy {
+	if in <= 0 || in >= 1 {
+		panic("2Q in ratio must be in (0, 1)")
+	}
+	if out <= 0 {
+		panic("2Q out ratio must be greater than zero")
+	}
+	return Policy{kind: policy2Q, in: in, out: out}
+}
+
+// Segmented LRU. New keys enter a probationary segment and move to a protected segment, holding
+// about protected times the capacity, on their second access; when the protected segment is full, its
+// least recently used entry moves back to the front of probation. Entries are evicted from probation
+// first, so keys accessed only once cannot flush the hot ones. 0.8 is a common choice.
+func PolicySLRU(protected float64) Policy {
+	if protected <= 0 || protected >= 1 {
+		panic("SLRU protected ratio must be in (0, 1)")
+	}
+	return Policy{kind: policySLRU, protect: protected}
+}
+
+// CLOCK (second chance) approximation of LRU. A hit only marks the entry as referenced instead of
+// moving it to the front of the list, which keeps the critical section of reads short. To evict, the
+// clock hand sweeps from the oldest entry: referenced entries get a second chance, moving to the
+// front with their mark cleared, and the first unmarked entry is evicted.
+func PolicyClock() Policy {
+	return Policy{kind: policyClock}
+}
+
+// Evicts entries in insertion order: neither hits nor overwrites move an entry, which suits
+// time-ordered buffers and deduplication windows and skips the list update of LRU on every read.
+func PolicyFIFO() Policy {
+	return Policy{kind: policyFIFO}
+}
+
+// Evicts a uniformly random entry, like the allkeys-random policy of Redis. Hits do not move entries,
+// so reads skip the list update of LRU; useful as a baseline in benchmarks and for uniform access
+// patterns, where recency carries no information.
+func PolicyRandom() Policy {
+	return Policy{kind: policyRandom}
+}
+
+// Evicts the most recently used entry other than the one just written. Hits move entries to the
+// front of the list as in LRU. For workloads that repeatedly iterate over more keys than fit, such as
+// cyclic scans over a table, LRU always evicts the key needed next and never hits, while MRU keeps a
+// stable subset of the cycle cached.
+func PolicyMRU() Policy {
+	return Policy{kind: policyMRU}
+}
+
+func (p Policy) String() string {
+	switch p.kind {
+	case policy2Q:
+		return fmt.Sprintf("2q in=%g out=%g", p.in, p.out)
+	case policySLRU:
+		return fmt.Sprintf("slru protected=%g", p.protect)
+	case policyClock:
+		return "clock"
+	case policyFIFO:
+		return "fifo"
+	case policyRandom:
+		return "random"
+	case policyMRU:
+		return "mru"
+	}
+	return "lru"
+}
+
+// Replaces LRU eviction with the given policy. Snapshot, Keys, Oldest and the other ordered views
+// then list entries in eviction order rather than strict recency order. The policy is reported by Stats.
+func WithPolicy[K comparable, V any](p Policy) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.policy = p
+		if p.kind == policy2Q {
+			c.ghosts = &ghostQueue[K]{ll: list.New(), keys: make(map[K]*list.Element)}
+		}
+	}
+}
+
+// Segmented policies keep their queues in the single list, the main queue (2Q's Am, SLRU's protected
+// segment) first and the admission queue (2Q's A1in, SLRU's probation) last, each ordered from most to
+// least recently admitted or used, so that the back of the list is the oldest admitted entry.
+type segments struct {
+	head *list.Element // first element of the admission queue; nil if it is empty
+	n    int           // entries in the admission queue
+}
+
+// Bounded FIFO of keys recently evicted from 2Q's admission queue.
+type ghostQueue[K comparable] struct {
+	ll   *list.List
+	keys map[K]*list.Element
+}
+
+func (g *ghostQueue[K]) add(key K, size int) {
+	if _, ok := g.keys[key]; ok {
+		return
+	}
+	g.keys[key] = g.ll.PushFront(key)
+	for g.ll.Len() > size {
+		g.remove(g.ll.Back().Value.(K))
+	}
+}
+
+// Forgets key, reporting whether it was remembered.
+func (g *ghostQueue[K]) remove(key K) bool {
+	ele, ok := g.keys[key]
+	if ok {
+		g.ll.Remove(ele)
+		delete(g.keys, key)
+	}
+	return ok
+}
+
+// Links a new entry into the list according to the eviction policy.
+func (c *Cache[K, V]) insertLocked(ent *entry[K, V]) *list.Element {
+	switch c.policy.kind {
+	case policy2Q:
+		if !c.ghosts.remove(ent.key) {
+			return c.admitLocked(ent)
+		}
+	case policySLRU:
+		return c.admitLocked(ent)
+	case policyRandom:
+		ele := c.ll.PushFront(ent)
+		ent.slot = len(c.slots)
+		c.slots = append(c.slots, ele)
+		return ele
+	}
+	return c.ll.PushFront(ent)
+}
+
+// Handles an access to an element of the admission queue, reporting whether it may move to the
+// front of the list: SLRU moves it out of probation, while 2Q keeps it in place.
+func (c *Cache[K, V]) accessAdmittedLocked(ele *list.Element) bool {
+	if c.policy.kind != policySLRU {
+		return false
+	}
+	if c.segs.head == ele {
+		c.segs.head = ele.Next()
+	}
+	c.segs.n--
+	ele.Value.(*entry[K, V]).admitted = false
+	return true
+}
+
+// Moves the least recently used protected entries back to probation while the protected segment
+// exceeds its share of the capacity.
+func (c *Cache[K, V]) demoteLocked() {
+	limit := max(1, int(c.policy.protect*float64(c.capacity)))
+	for c.ll.Len()-c.segs.n > limit {
+		ele := c.ll.Back()
+		if c.segs.head != nil {
+			ele = c.segs.head.Prev()
+		}
+		ele.Value.(*entry[K, V]).admitted = true
+		c.segs.head = ele
+		c.segs.n++
+	}
+}
+
+// Links a new entry at the front of the admission queue.
+func (c *Cache[K, V]) admitLocked(ent *entry[K, V]) *list.Element {
+	var ele *list.Element
+	if c.segs.head != nil {
+		ele = c.ll.InsertBefore(ent, c.segs.head)
+	} else {
+		ele = c.ll.PushBack(ent)
+	}
+	ent.admitted = true
+	c.segs.head = ele
+	c.segs.n++
+	return ele
+}
+
+// Unlinks an element from the queues of the eviction policy before it is removed from the list.
+func (c *Cache[K, V]) unlinkLocked(ele *list.Element, reason EvictionReason) {
+	ent := ele.Value.(*entry[K, V])
+	if c.policy.kind == policyRandom {
+		last := c.slots[len(c.slots)-1]
+		c.slots[ent.slot] = last
+		last.Value.(*entry[K, V]).slot = ent.slot
+		c.slots[len(c.slots)-1] = nil
+		c.slots = c.slots[:len(c.slots)-1]
+	}
+	if !ent.admitted {
+		return
+	}
+	if c.segs.head == ele {
+		c.segs.head = ele.Next()
+	}
+	c.segs.n--
+	if c.policy.kind == policy2Q && reason == EvictionCapacity && !ent.tombstone {
+		c.ghosts.add(ent.key, max(1, int(c.policy.out*float64(c.capacity))))
+	}
+}
+
+// Returns the first candidate for eviction, before vetoes and residency protection are considered.
+func (c *Cache[K, V]) candidateLocked() *list.Element {
+	if c.policy.kind == policyMRU {
+		// Keep the entry that was just written.
+		front := c.ll.Front()
+		if front != nil && front.Next() != nil {
+			return front.Next()
+		}
+		return front
+	}
+	if c.policy.kind == policyRandom && len(c.slots) > 0 {
+		return c.slots[rand.IntN(len(c.slots))]
+	}
+	if c.policy.kind == policyClock {
+		// After a full sweep, every mark is cleared.
+		for n := c.ll.Len(); n > 0; n-- {
+			ele := c.ll.Back()
+			ent := ele.Value.(*entry[K, V])
+			if !ent.referenced {
+				return ele
+			}
+			ent.referenced = false
+			if c.ranks != nil {
+				c.ranks.promote(&ent.gen)
+			}
+			c.ll.MoveToFront(ele)
+		}
+	}
+	if c.policy.kind == policy2Q && c.segs.n <= max(1, int(c.policy.in*float64(c.capacity))) {
+		// The admission queue is within its share: evict the least recently used entry of Am.
+		if c.segs.head == nil {
+			return c.ll.Back()
+		}
+		if prev := c.segs.head.Prev(); prev != nil {
+			return prev
+		}
+	}
+	return c.ll.Back()
+}
+
+// Returns the candidate for eviction examined after ele when ele is protected or vetoed.
+func (c *Cache[K, V]) nextCandidateLocked(ele *list.Element) *list.Element {
+	if c.policy.kind == policyMRU {
+		return ele.Next()
+	}
+	return ele.Prev()
+}
+
+// Forgets the queues of the eviction policy once the list is cleared.
+func (c *Cache[K, V]) resetPolicyLocked() {
+	c.segs = segments{}
+	clear(c.slots)
+	c.slots = c.slots[:0]
+	if c.ghosts != nil {
+		c.ghosts.ll.Init()
+		clear(c.ghosts.keys)
+	}
+}
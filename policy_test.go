@@ -0,0 +1,172 @@
+package goutte_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCachePolicy2Q(t *testing.T) {
+	cache := goutte.NewCache(4, goutte.WithPolicy[string, int](goutte.Policy2Q(0.25, 0.5)))
+	defer cache.Close()
+
+	for _, key := range []string{"a", "b", "c", "d"} {
+		cache.Set(key, 0)
+	}
+	// A hit does not move an entry of the admission queue, which is FIFO.
+	cache.Get("a")
+	cache.Set("e", 0)
+	if cache.Contains("a") {
+		t.Error("Expected 'a' to be evicted first from the admission queue")
+	}
+
+	// 'a' is remembered by the ghost queue, so writing it again admits it to the main queue,
+	// where a scan of new keys cannot flush it.
+	cache.Set("a", 1)
+	for i := 0; i < 20; i++ {
+		cache.Set(fmt.Sprintf("scan-%d", i), i)
+	}
+	if val, ok := cache.Get("a"); !ok || val != 1 {
+		t.Errorf("Expected 'a' to survive the scan, got %v (found: %v)", val, ok)
+	}
+	if cache.Len() != 4 {
+		t.Errorf("Expected 4 entries, got %d", cache.Len())
+	}
+	if p := cache.Stats().Policy; p != "2q in=0.25 out=0.5" {
+		t.Errorf("Unexpected policy %q", p)
+	}
+}
+
+func TestCachePolicySLRU(t *testing.T) {
+	cache := goutte.NewCache(4, goutte.WithPolicy[string, int](goutte.PolicySLRU(0.5)))
+	defer cache.Close()
+
+	cache.Set("hot1", 1)
+	cache.Set("hot2", 2)
+	cache.Get("hot1")
+	cache.Get("hot2")
+
+	// Keys accessed once only churn the probationary segment.
+	for i := 0; i < 20; i++ {
+		cache.Set(fmt.Sprintf("once-%d", i), i)
+	}
+	if !cache.Contains("hot1") || !cache.Contains("hot2") {
+		t.Error("Expected the protected entries to survive the one-hit keys")
+	}
+
+	// A third protected entry demotes the least recently used one to probation, where it is
+	// evicted next.
+	cache.Get("once-19")
+	cache.Set("new", 0)
+	cache.Set("newer", 0)
+	cache.Set("newest", 0)
+	if cache.Contains("hot1") || !cache.Contains("hot2") || !cache.Contains("once-19") {
+		t.Errorf("Expected 'hot1' to be demoted and evicted, got keys %v", slices.Collect(cache.Keys()))
+	}
+}
+
+func TestCachePolicyClock(t *testing.T) {
+	cache := goutte.NewCache(3, goutte.WithPolicy[string, int](goutte.PolicyClock()))
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+
+	// A hit marks 'a' without moving it.
+	cache.Get("a")
+	if keys := slices.Collect(cache.Keys()); !slices.Equal(keys, []string{"a", "b", "c"}) {
+		t.Errorf("Expected the hit not to reorder the entries, got %v", keys)
+	}
+	if n := cache.Stats().Promotions; n != 0 {
+		t.Errorf("Expected no promotion, got %d", n)
+	}
+
+	// 'a' gets a second chance, so 'b' is evicted.
+	cache.Set("d", 4)
+	if keys := slices.Collect(cache.Keys()); !slices.Equal(keys, []string{"c", "d", "a"}) {
+		t.Errorf("Expected 'b' to be evicted and 'a' to move to the front, got %v", keys)
+	}
+}
+
+func TestCachePolicyFIFO(t *testing.T) {
+	cache := goutte.NewCache(2, goutte.WithPolicy[string, int](goutte.PolicyFIFO()))
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Get("a")
+	cache.Set("a", 10)
+
+	cache.Set("c", 3)
+	if cache.Contains("a") || !cache.Contains("b") || !cache.Contains("c") {
+		t.Errorf("Expected the first inserted key to be evicted, got keys %v", slices.Collect(cache.Keys()))
+	}
+}
+
+func TestCachePolicyRandom(t *testing.T) {
+	cache := goutte.NewCache(10, goutte.WithPolicy[int, int](goutte.PolicyRandom()))
+	defer cache.Close()
+
+	// Under LRU, the first 10 keys would always be evicted by the next 10.
+	survivors := 0
+	for round := 0; round < 20; round++ {
+		cache.Dump()
+		for i := 0; i < 20; i++ {
+			cache.Set(i, i)
+		}
+		if cache.Len() != 10 {
+			t.Fatalf("Expected 10 entries, got %d", cache.Len())
+		}
+		for i := 0; i < 10; i++ {
+			if cache.Contains(i) {
+				survivors++
+			}
+		}
+	}
+	if survivors == 0 {
+		t.Error("Expected some of the first keys to survive random eviction")
+	}
+
+	for i := 0; i < 10; i++ {
+		cache.Delete(i)
+	}
+	for i := 20; i < 40; i++ {
+		cache.Set(i, i)
+	}
+	if cache.Len() != 10 {
+		t.Errorf("Expected 10 entries after deletes, got %d", cache.Len())
+	}
+}
+
+func TestCachePolicyMRU(t *testing.T) {
+	hits := func(opts ...goutte.Option[int, int]) uint64 {
+		cache := goutte.NewCache(5, opts...)
+		defer cache.Close()
+		// Cyclic scan over 6 keys with a read-through pattern.
+		for round := 0; round < 10; round++ {
+			for key := 0; key < 6; key++ {
+				if _, ok := cache.Get(key); !ok {
+					cache.Set(key, key)
+				}
+			}
+		}
+		return cache.Stats().Hits
+	}
+
+	if n := hits(); n != 0 {
+		t.Errorf("Expected LRU never to hit on a cyclic scan, got %d hits", n)
+	}
+	if n := hits(goutte.WithPolicy[int, int](goutte.PolicyMRU())); n < 30 {
+		t.Errorf("Expected MRU to hit on most of the scan, got %d hits", n)
+	}
+
+	cache := goutte.NewCache(2, goutte.WithPolicy[string, int](goutte.PolicyMRU()))
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	if cache.Contains("b") || !cache.Contains("a") || !cache.Contains("c") {
+		t.Errorf("Expected the most recently used 'b' to be evicted, got keys %v", slices.Collect(cache.Keys()))
+	}
+}
@@ -0,0 +1,93 @@
+package goutte
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+type promotionMode int
+
+const (
+	promoteAlways promotionMode = iota
+	promoteEveryN
+	promoteColdHalf
+	promoteRandom
+)
+
+// Strategy deciding whether a hit moves the entry to the front of the list; see WithPromotion.
+// The zero value promotes on every hit, which is strict LRU.
+type Promotion struct {
+	mode  promotionMode
+	every uint64
+	p     float64
+}
+
+// Promotes on every hit (strict LRU). This is the default.
+func PromoteAlways() Promotion {
+	return Promotion{}
+}
+
+// Promotes an entry on every nth hit it receives.
+func PromoteEveryN(n int) Promotion {
+	if n <= 0 {
+		panic("promotion interval must be greater than zero")
+	}
+	return Promotion{mode: promoteEveryN, every: uint64(n)}
+}
+
+// Promotes an entry only if it is approximately in the colder (least recently used) half of the list,
+// estimated from the number of promotions and inserts since it was last moved to the front.
+func PromoteColdHalf() Promotion {
+	return Promotion{mode: promoteColdHalf}
+}
+
+// Promotes on a hit with probability p.
+func PromoteWithProbability(p float64) Promotion {
+	if p <= 0 || p > 1 {
+		panic("promotion probability must be in (0, 1]")
+	}
+	return Promotion{mode: promoteRandom, p: p}
+}
+
+func (p Promotion) String() string {
+	switch p.mode {
+	case promoteEveryN:
+		return fmt.Sprintf("every %d hits", p.every)
+	case promoteColdHalf:
+		return "cold half"
+	case promoteRandom:
+		return fmt.Sprintf("probability %g", p.p)
+	}
+	return "always"
+}
+
+// Replaces the "move to front on every hit" behavior with the given strategy, trading strict LRU
+// ordering for less list churn and shorter lock hold times on read-heavy workloads. Writes always
+// promote. The strategy and the number of promotions are reported by Stats.
+func WithPromotion[K comparable, V any](p Promotion) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.promotion = p
+	}
+}
+
+// Decides whether a hit on ent promotes it, according to the promotion strategy.
+func (c *Cache[K, V]) shouldPromoteLocked(ent *entry[K, V]) bool {
+	switch c.promotion.mode {
+	case promoteEveryN:
+		ent.promo++
+		return ent.promo%c.promotion.every == 0
+	case promoteColdHalf:
+		return c.promoStamp-ent.promo >= uint64(c.ll.Len()/2)
+	case promoteRandom:
+		return rand.Float64() < c.promotion.p
+	}
+	return true
+}
+
+// Stamps an entry moved to the front, for PromoteColdHalf.
+func (c *Cache[K, V]) stampLocked(ent *entry[K, V]) {
+	if c.promotion.mode == promoteColdHalf {
+		c.promoStamp++
+		ent.promo = c.promoStamp
+	}
+}
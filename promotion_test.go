@@ -0,0 +1,84 @@
+package goutte_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCachePromoteEveryN(t *testing.T) {
+	cache := goutte.NewCache[string, int](2, goutte.WithPromotion[string, int](goutte.PromoteEveryN(2)))
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+
+	// The first hit does not promote 'a', so it is evicted next.
+	cache.Get("a")
+	cache.Set("c", 3)
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected 'a' to be evicted after a single hit")
+	}
+
+	// The second hit on 'b' promotes it.
+	cache.Get("b")
+	cache.Get("b")
+	cache.Set("d", 4)
+	if _, ok := cache.Get("b"); !ok {
+		t.Error("Expected 'b' to be promoted by its second hit")
+	}
+
+	stats := cache.Stats()
+	if stats.Promotion != "every 2 hits" || stats.Promotions != 1 {
+		t.Errorf("Expected 1 promotion with strategy 'every 2 hits', got %+v", stats)
+	}
+}
+
+func TestCachePromoteColdHalf(t *testing.T) {
+	cache := goutte.NewCache[int, int](8, goutte.WithPromotion[int, int](goutte.PromoteColdHalf()))
+	defer cache.Close()
+	for i := 0; i < 8; i++ {
+		cache.Set(i, i)
+	}
+
+	// Hot entries are left in place, cold ones are promoted.
+	cache.Get(7)
+	cache.Get(6)
+	cache.Get(0)
+	cache.Get(1)
+
+	if stats := cache.Stats(); stats.Promotion != "cold half" || stats.Promotions != 2 {
+		t.Errorf("Expected 2 promotions with strategy 'cold half', got %+v", stats)
+	}
+	if keys := slices.Collect(cache.Keys()); keys[6] != 0 || keys[7] != 1 {
+		t.Errorf("Expected 0 and 1 to be the most recently used, got %v", keys)
+	}
+}
+
+func TestCachePromoteWithProbability(t *testing.T) {
+	cache := goutte.NewCache[int, int](2, goutte.WithPromotion[int, int](goutte.PromoteWithProbability(0.5)))
+	defer cache.Close()
+	cache.Set(1, 1)
+
+	for i := 0; i < 1000; i++ {
+		cache.Get(1)
+	}
+	if stats := cache.Stats(); stats.Promotions < 350 || stats.Promotions > 650 {
+		t.Errorf("Expected about 500 promotions out of 1000 hits, got %+v", stats)
+	}
+}
+
+func TestCachePromoteAlwaysDefault(t *testing.T) {
+	cache := goutte.NewCache[int, int](2)
+	defer cache.Close()
+	cache.Set(1, 1)
+	cache.Get(1)
+	cache.Get(2)
+
+	want := goutte.Stats{Hits: 1, Misses: 1, Promotions: 1, Promotion: "always", Policy: "lru", Size: 1, Config: goutte.Config{Capacity: 2}}
+	stats := cache.Stats()
+	stats.Contention = goutte.ContentionStats{} // set when built with goutte_contention
+	if stats != want {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
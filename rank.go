@@ -0,0 +1,120 @@
+package goutte
+
+import "math/bits"
+
+// Number of recency segments used to approximate list positions.
+const rankSegments = 32
+
+// Hits recorded for a range of LRU positions.
+type RankBucket struct {
+	MaxRank int    // inclusive upper bound of the range, as a 0-based position from the most recently used end
+	Hits    uint64 // number of hits whose approximate position fell in the range
+}
+
+// Approximates list positions without walking the list.
+// Every insert or promotion stamps the entry with the current generation, and the generation advances
+// after roughly capacity/rankSegments stamps. The position of an entry is then estimated from the number
+// of entries stamped with newer generations. Generations older than the tracked window share a tail bucket.
+type rankTracker struct {
+	segSize int               // stamps per generation
+	gen     uint64            // current generation
+	inGen   int               // stamps issued in the current generation
+	counts  [rankSegments]int // live entries per tracked generation, indexed by gen % rankSegments
+	tail    int               // live entries older than the tracked generations
+	hist    []uint64          // hits per power-of-two rank bucket
+}
+
+func newRankTracker(capacity int) *rankTracker {
+	rt := &rankTracker{}
+	rt.resize(capacity)
+	return rt
+}
+
+func (rt *rankTracker) resize(capacity int) {
+	rt.segSize = max(1, capacity/rankSegments)
+}
+
+func (rt *rankTracker) reset() {
+	rt.gen = 0
+	rt.inGen = 0
+	rt.counts = [rankSegments]int{}
+	rt.tail = 0
+}
+
+// Reports whether gen is still within the tracked window.
+func (rt *rankTracker) tracked(gen uint64) bool {
+	return gen+rankSegments > rt.gen
+}
+
+// Stamps a new entry with the current generation.
+func (rt *rankTracker) insert(gen *uint64) {
+	*gen = rt.gen
+	rt.counts[rt.gen%rankSegments]++
+	rt.inGen++
+	if rt.inGen >= rt.segSize {
+		rt.advance()
+	}
+}
+
+// Starts a new generation, folding the one falling out of the window into the tail.
+func (rt *rankTracker) advance() {
+	rt.gen++
+	rt.inGen = 0
+	slot := rt.gen % rankSegments
+	rt.tail += rt.counts[slot]
+	rt.counts[slot] = 0
+}
+
+// Forgets an entry stamped with gen.
+func (rt *rankTracker) remove(gen uint64) {
+	if rt.tracked(gen) {
+		rt.counts[gen%rankSegments]--
+	} else {
+		rt.tail--
+	}
+}
+
+// Restamps an entry that moved to the front of the list.
+func (rt *rankTracker) promote(gen *uint64) {
+	rt.remove(*gen)
+	rt.insert(gen)
+}
+
+// Estimates the position of an entry stamped with gen and adds it to the histogram.
+func (rt *rankTracker) recordHit(gen uint64) {
+	rank := 0
+	if rt.tracked(gen) {
+		for g := gen + 1; g <= rt.gen; g++ {
+			rank += rt.counts[g%rankSegments]
+		}
+		rank += rt.counts[gen%rankSegments] / 2
+	} else {
+		for _, n := range rt.counts {
+			rank += n
+		}
+		rank += rt.tail / 2
+	}
+
+	i := bits.Len(uint(rank))
+	for len(rt.hist) <= i {
+		rt.hist = append(rt.hist, 0)
+	}
+	rt.hist[i]++
+}
+
+// Returns the histogram of hit positions recorded with WithRankHistogram.
+// Bucket i covers positions up to 2^i - 1, so the first bucket holds hits on the most recently used entry.
+// It returns nil if rank tracking is not enabled.
+func (c *Cache[K, V]) HitRanks() []RankBucket {
+	c.lock()
+	defer c.unlock()
+
+	if c.ranks == nil {
+		return nil
+	}
+	buckets := make([]RankBucket, len(c.ranks.hist))
+	for i, hits := range c.ranks.hist {
+		buckets[i] = RankBucket{MaxRank: 1<<i - 1, Hits: hits}
+	}
+	return buckets
+}
@@ -0,0 +1,46 @@
+package goutte_test
+
+import (
+	"testing"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheHitRanks(t *testing.T) {
+	cache := goutte.NewCache[int, int](256, goutte.WithRankHistogram[int, int]())
+	defer cache.Close()
+	for i := 0; i < 256; i++ {
+		cache.Set(i, i)
+	}
+
+	// The most recently inserted key sits at the front of the list.
+	cache.Get(255)
+	// The oldest key sits near the back of the list.
+	cache.Get(0)
+
+	buckets := cache.HitRanks()
+	var total uint64
+	for _, b := range buckets {
+		total += b.Hits
+	}
+	if total != 2 {
+		t.Fatalf("Expected 2 recorded hits, got %d in %+v", total, buckets)
+	}
+	if buckets[0].Hits+buckets[1].Hits+buckets[2].Hits+buckets[3].Hits != 1 {
+		t.Errorf("Expected the hit on the newest key in a low rank bucket, got %+v", buckets)
+	}
+	if last := buckets[len(buckets)-1]; last.Hits != 1 || last.MaxRank < 127 {
+		t.Errorf("Expected the hit on the oldest key in a high rank bucket, got %+v", buckets)
+	}
+}
+
+func TestCacheHitRanksDisabled(t *testing.T) {
+	cache := goutte.NewCache[int, int](2)
+	defer cache.Close()
+	cache.Set(1, 1)
+	cache.Get(1)
+
+	if buckets := cache.HitRanks(); buckets != nil {
+		t.Errorf("Expected no histogram without WithRankHistogram, got %+v", buckets)
+	}
+}
@@ -0,0 +1,86 @@
+package goutte
+
+import (
+	"container/list"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+)
+
+// Accesses recorded by each read buffer stripe before it is drained.
+const readBufferSize = 16
+
+// Striped, lossy buffer of the hits served under the read lock, see WithReadBuffer.
+type readBuffer struct {
+	stripes []readStripe
+	pending atomic.Int64 // recorded hits not drained yet
+}
+
+type readStripe struct {
+	mu  sync.Mutex
+	buf [readBufferSize]*list.Element
+	n   int
+	_   [64]byte // keeps stripes on separate cache lines
+}
+
+// Like WithSharedReads, but hits are recorded in striped buffers, as in Caffeine, and replayed in
+// batches under the exclusive lock, in the order each stripe recorded them, instead of marking the
+// entries for a second chance. LRU ordering thus stays close to exact while reads rarely contend: a
+// hit takes the read lock and the lock of a randomly chosen stripe. The buffers are drained before
+// every write, and by the reader that fills a stripe if the exclusive lock is free. Hits are dropped
+// when their stripe is busy or full, so a few promotions may be lost under heavy load; they are still
+// counted in Stats.
+func WithReadBuffer[K comparable, V any]() Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.sharedReads = true
+		c.readBuf = &readBuffer{stripes: make([]readStripe, defaultStripes())}
+	}
+}
+
+// Records a hit on ele, reporting whether its stripe is full and should be drained.
+// It is called with the read lock held.
+func (b *readBuffer) record(ele *list.Element) bool {
+	s := &b.stripes[rand.IntN(len(b.stripes))]
+	if !s.mu.TryLock() {
+		return false
+	}
+	defer s.mu.Unlock()
+
+	if s.n == readBufferSize {
+		return true
+	}
+	s.buf[s.n] = ele
+	s.n++
+	b.pending.Add(1)
+	return s.n == readBufferSize
+}
+
+// Drains the read buffer if the exclusive lock is free, so readers never wait for it.
+func (c *Cache[K, V]) tryDrainReads() {
+	if !c.mu.TryLock() {
+		return
+	}
+	c.drainReadsLocked()
+	c.unlock()
+}
+
+// Promotes the elements recorded by the read buffer that are still in the cache, and empties it.
+func (c *Cache[K, V]) drainReadsLocked() {
+	if c.readBuf.pending.Load() == 0 {
+		return
+	}
+	for i := range c.readBuf.stripes {
+		s := &c.readBuf.stripes[i]
+		s.mu.Lock()
+		for _, ele := range s.buf[:s.n] {
+			ent := ele.Value.(*entry[K, V])
+			if c.cache[ent.key] == ele && c.promoteLocked(ele) {
+				c.promotions++
+			}
+		}
+		clear(s.buf[:s.n])
+		c.readBuf.pending.Add(int64(-s.n))
+		s.n = 0
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,69 @@
+package goutte_test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheReadBuffer(t *testing.T) {
+	cache := goutte.NewCache(3, goutte.WithReadBuffer[string, int]())
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+
+	// Hits recorded by different stripes may be replayed in any order, so each write follows one hit.
+	cache.Get("a")
+	// The buffered hit is replayed before the eviction, so 'b' is now the least recently used.
+	cache.Set("d", 4)
+	cache.Get("c")
+	cache.Set("e", 5)
+	if keys := slices.Collect(cache.Keys()); !slices.Equal(keys, []string{"d", "c", "e"}) {
+		t.Errorf("Expected keys [d c e], got %v", keys)
+	}
+	if stats := cache.Stats(); stats.Hits != 2 || stats.Promotions != 2 {
+		t.Errorf("Expected 2 hits and 2 promotions, got %+v", stats)
+	}
+
+	// Hits on entries removed in the meantime are ignored.
+	cache.Get("e")
+	cache.Delete("e")
+	cache.Set("f", 6)
+	cache.Set("g", 7)
+	if keys := slices.Collect(cache.Keys()); !slices.Equal(keys, []string{"c", "f", "g"}) {
+		t.Errorf("Expected keys [c f g], got %v", keys)
+	}
+}
+
+func TestCacheReadBufferConcurrency(t *testing.T) {
+	cache := goutte.NewCache(64, goutte.WithReadBuffer[int, int]())
+	defer cache.Close()
+	for i := 0; i < 64; i++ {
+		cache.Set(i, i)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				key := (g*31 + i) % 96
+				if val, ok := cache.Get(key); ok && val != key {
+					t.Errorf("Expected key %d to have value %d, got %d", key, key, val)
+				}
+				if i%10 == 0 {
+					cache.Set(key, key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := cache.Len(); n != 64 {
+		t.Errorf("Expected 64 entries, got %d", n)
+	}
+}
@@ -0,0 +1,137 @@
+package goutte
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Named collection of caches with heterogeneous key and value types.
+// Caches are registered through the generic Register and RegisterCache functions, which return a
+// RegistryKey, and retrieved by passing that key to Lookup, so callers get a typed *Cache back instead
+// of casting values out of a shared any-typed cache. Lookups take a key rather than a name alone, as
+// in Lookup[V](reg, name), because Go cannot infer the key type of the cache from a name.
+type TypedRegistry struct {
+	mu       sync.Mutex
+	caches   map[string]registeredCache
+	defaults Config // settings of the caches created by Register
+}
+
+// Type-erased registry entry.
+type registeredCache struct {
+	cache any
+	close func()
+}
+
+// Typed handle to a cache registered in a TypedRegistry, carrying its name and its key and value types.
+// Keys returned by Register and RegisterCache always match the registered cache; passing them to
+// Lookup with other types does not compile.
+type RegistryKey[K comparable, V any] struct {
+	name string
+}
+
+// Returns a key for the cache registered under name, e.g. to declare it as a package variable instead
+// of passing around the key returned by Register. Its types are not checked against the registered
+// cache until Lookup, which reports false if they differ.
+func NewRegistryKey[K comparable, V any](name string) RegistryKey[K, V] {
+	return RegistryKey[K, V]{name: name}
+}
+
+// Returns the name under which the cache is registered.
+func (k RegistryKey[K, V]) Name() string {
+	return k.name
+}
+
+// Creates an empty registry; caches created through Register default to the given capacity.
+func NewTypedRegistry(defaultCapacity int) *TypedRegistry {
+	if defaultCapacity <= 0 {
+		panic("default capacity must be greater than zero")
+	}
+	return &TypedRegistry{
+		caches:   make(map[string]registeredCache),
+		defaults: Config{Capacity: defaultCapacity},
+	}
+}
+
+// Creates an empty registry whose caches created through Register default to the settings of
+// defaults, e.g. a default TTL, promotion strategy or diagnostics shared by every cache of an
+// application. It returns a *ConfigError if defaults is invalid or sets Shards.
+func NewTypedRegistryFromConfig(defaults Config) (*TypedRegistry, error) {
+	if err := defaults.Validate(); err != nil {
+		return nil, err
+	}
+	if defaults.Shards > 0 {
+		return nil, &ConfigError{Field: "shards", Reason: "registries hold unsharded caches"}
+	}
+	return &TypedRegistry{
+		caches:   make(map[string]registeredCache),
+		defaults: defaults,
+	}, nil
+}
+
+// Creates a cache with the registry's default settings, overridden by opts, registers it under name
+// and returns it along with its key. It returns ErrDuplicateName if the name is already taken.
+func Register[K comparable, V any](reg *TypedRegistry, name string, opts ...Option[K, V]) (*Cache[K, V], RegistryKey[K, V], error) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if _, ok := reg.caches[name]; ok {
+		return nil, RegistryKey[K, V]{}, fmt.Errorf("%w: %q", ErrDuplicateName, name)
+	}
+	// The defaults were validated when the registry was created.
+	c, _ := NewFromConfig(reg.defaults, opts...)
+	reg.caches[name] = registeredCache{cache: c, close: c.Close}
+	return c, RegistryKey[K, V]{name: name}, nil
+}
+
+// Registers an existing cache under name, e.g. one that needs a non-default capacity, and returns its
+// key. It returns ErrDuplicateName if the name is already taken.
+func RegisterCache[K comparable, V any](reg *TypedRegistry, name string, c *Cache[K, V]) (RegistryKey[K, V], error) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if _, ok := reg.caches[name]; ok {
+		return RegistryKey[K, V]{}, fmt.Errorf("%w: %q", ErrDuplicateName, name)
+	}
+	reg.caches[name] = registeredCache{cache: c, close: c.Close}
+	return RegistryKey[K, V]{name: name}, nil
+}
+
+// Retrieves the cache registered under the name of key.
+// The second result is false if no cache is registered under that name, or if the cache registered
+// under it has other key or value types, e.g. for a key made with NewRegistryKey.
+func Lookup[K comparable, V any](reg *TypedRegistry, key RegistryKey[K, V]) (*Cache[K, V], bool) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	rc, ok := reg.caches[key.name]
+	if !ok {
+		return nil, false
+	}
+	c, ok := rc.cache.(*Cache[K, V])
+	return c, ok
+}
+
+// Returns the registered names in sorted order.
+func (reg *TypedRegistry) Names() []string {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	names := make([]string, 0, len(reg.caches))
+	for name := range reg.caches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Closes every registered cache and empties the registry.
+func (reg *TypedRegistry) Close() {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	for name, rc := range reg.caches {
+		rc.close()
+		delete(reg.caches, name)
+	}
+}
@@ -0,0 +1,105 @@
+package goutte_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+type userProfile struct {
+	Name string
+}
+
+func TestTypedRegistry(t *testing.T) {
+	reg := goutte.NewTypedRegistry(10)
+	defer reg.Close()
+
+	profiles, profilesKey, err := goutte.Register[string, userProfile](reg, "profiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	profiles.Set("u1", userProfile{Name: "Ada"})
+
+	counters := goutte.NewCache[int, int](2)
+	countersKey, err := goutte.RegisterCache(reg, "counters", counters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := countersKey.Name(); name != "counters" {
+		t.Errorf("Expected key name 'counters', got %q", name)
+	}
+
+	got, ok := goutte.Lookup(reg, profilesKey)
+	if !ok {
+		t.Fatal("Expected to find cache 'profiles'")
+	}
+	if val, ok := got.Get("u1"); !ok || val.Name != "Ada" {
+		t.Errorf("Expected key 'u1' to have name 'Ada', got %v (found: %v)", val, ok)
+	}
+
+	if got, ok := goutte.Lookup(reg, countersKey); !ok || got != counters {
+		t.Errorf("Expected to find cache 'counters', got %p (found: %v)", got, ok)
+	}
+	if got, ok := goutte.Lookup(reg, goutte.NewRegistryKey[string, userProfile]("profiles")); !ok || got != profiles {
+		t.Errorf("Expected a declared key to find cache 'profiles', got %p (found: %v)", got, ok)
+	}
+	if _, ok := goutte.Lookup(reg, goutte.NewRegistryKey[string, string]("profiles")); ok {
+		t.Error("Expected lookup with the wrong value type to fail")
+	}
+	if _, ok := goutte.Lookup(reg, goutte.NewRegistryKey[string, userProfile]("missing")); ok {
+		t.Error("Expected lookup of an unknown name to fail")
+	}
+
+	if names := reg.Names(); !reflect.DeepEqual(names, []string{"counters", "profiles"}) {
+		t.Errorf("Expected names [counters profiles], got %v", names)
+	}
+}
+
+func TestTypedRegistryDuplicateName(t *testing.T) {
+	reg := goutte.NewTypedRegistry(10)
+	defer reg.Close()
+
+	if _, _, err := goutte.Register[string, int](reg, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := goutte.Register[int, string](reg, "a"); !errors.Is(err, goutte.ErrDuplicateName) {
+		t.Errorf("Expected ErrDuplicateName, got %v", err)
+	}
+}
+
+func TestTypedRegistryDefaults(t *testing.T) {
+	reg, err := goutte.NewTypedRegistryFromConfig(goutte.Config{Capacity: 8, DefaultTTL: goutte.Duration(time.Minute), EventLog: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reg.Close()
+
+	sessions, _, err := goutte.Register[string, int](reg, "sessions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions.Set("a", 1)
+	if _, exp, _ := sessions.GetWithExpiration("a"); exp.IsZero() {
+		t.Error("Expected the default TTL of the registry to apply")
+	}
+	if cfg := sessions.Stats().Config; cfg.Capacity != 8 || cfg.EventLog != 4 {
+		t.Errorf("Expected the default settings of the registry, got %+v", cfg)
+	}
+
+	// Options override the defaults.
+	tokens, _, err := goutte.Register(reg, "tokens", goutte.WithDefaultTTL[string, string](time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens.Set("t", "x")
+	if _, exp, _ := tokens.GetWithExpiration("t"); time.Until(exp) < 30*time.Minute {
+		t.Errorf("Expected the TTL option to override the default, got expiration %v", exp)
+	}
+
+	if _, err := goutte.NewTypedRegistryFromConfig(goutte.Config{}); !errors.Is(err, goutte.ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package goutte
+
+import "sync"
+
+// Why an entry left the cache, as reported to the callback registered with WithOnRemoval.
+type EvictionReason int
+
+const (
+	// The entry was evicted to respect the capacity.
+	EvictionCapacity EvictionReason = iota
+	// The entry's TTL elapsed.
+	EvictionExpired
+	// The entry was removed by Delete, DeleteMany or SoftDelete.
+	EvictionDeleted
+	// The entry's value was overwritten by a write to the same key.
+	EvictionReplaced
+	// The entry was removed by Dump.
+	EvictionCleared
+)
+
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictionCapacity:
+		return "capacity"
+	case EvictionExpired:
+		return "expired"
+	case EvictionDeleted:
+		return "deleted"
+	case EvictionReplaced:
+		return "replaced"
+	case EvictionCleared:
+		return "cleared"
+	}
+	return "unknown"
+}
+
+// Entry removed from the cache, pending delivery to the OnRemoval callback.
+type removedEntry[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictionReason
+}
+
+// Queues the removal of ent for delivery once the lock is released, and records it in the event log
+// and the counters. Soft-deleted entries are always reported as deleted, whatever finally removed
+// their tombstone.
+func (c *Cache[K, V]) notifyRemovalLocked(ent *entry[K, V], reason EvictionReason) {
+	if ent.tombstone {
+		reason = EvictionDeleted
+	}
+	switch reason {
+	case EvictionExpired:
+		c.expirations++
+	case EvictionCapacity:
+		c.evictions++
+	}
+	if reason != EvictionReplaced {
+		c.logEventLocked(OpRemove, ent.key, ent.value, reason)
+	}
+	if c.onRemoval == nil {
+		return
+	}
+	c.removed = append(c.removed, removedEntry[K, V]{key: ent.key, value: ent.value, reason: reason})
+}
+
+// Releases the cache lock, then delivers removals collected while it was held to the OnRemoval
+// callback, so the callback may safely call back into the cache.
+func (c *Cache[K, V]) unlock() {
+	removed := c.removed
+	c.removed = nil
+	c.mu.Unlock()
+
+	for _, r := range removed {
+		c.onRemoval(r.key, r.value, r.reason)
+	}
+}
+
+// Maximum number of removals queued for asynchronous delivery, see DeleteMany.
+const removalQueueSize = 1024
+
+// Removals delivered asynchronously, in order, by a worker goroutine that runs while the queue is not
+// empty, so no goroutine outlives the deliveries.
+type removalQueue[K comparable, V any] struct {
+	mu      sync.Mutex
+	cond    sync.Cond // signaled when the worker takes queued removals
+	entries []removedEntry[K, V]
+	running bool // whether the worker is running
+}
+
+// Releases the cache lock like unlock, but hands the removals over to the worker instead of
+// delivering them on the caller's goroutine. Once removalQueueSize removals are waiting, the caller
+// blocks until the worker catches up, so a slow callback cannot grow memory without bound.
+func (c *Cache[K, V]) unlockAsync() {
+	removed := c.removed
+	c.removed = nil
+	c.mu.Unlock()
+	if len(removed) == 0 {
+		return
+	}
+
+	q := &c.asyncRemovals
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.cond.L == nil {
+		q.cond.L = &q.mu
+	}
+	for _, r := range removed {
+		for len(q.entries) >= removalQueueSize {
+			q.cond.Wait()
+		}
+		q.entries = append(q.entries, r)
+		if !q.running {
+			q.running = true
+			go c.deliverRemovals()
+		}
+	}
+}
+
+// Delivers queued removals to the OnRemoval callback until the queue is empty.
+func (c *Cache[K, V]) deliverRemovals() {
+	q := &c.asyncRemovals
+	for {
+		q.mu.Lock()
+		removed := q.entries
+		q.entries = nil
+		if len(removed) == 0 {
+			q.running = false
+			q.mu.Unlock()
+			return
+		}
+		q.cond.Broadcast()
+		q.mu.Unlock()
+
+		for _, r := range removed {
+			c.onRemoval(r.key, r.value, r.reason)
+		}
+	}
+}
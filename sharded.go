@@ -0,0 +1,227 @@
+package goutte
+
+import (
+	"hash/maphash"
+	"math/bits"
+	"runtime"
+	"time"
+)
+
+// Cache split into independently locked shards, each a *Cache holding a share of the capacity, so
+// operations on different keys rarely contend on the same mutex. Keys are assigned to shards by hash.
+// Eviction and expiration happen per shard: the least recently used entry of the shard receiving a
+// write is evicted, which approximates LRU over the whole cache.
+type ShardedCache[K comparable, V any] struct {
+	shards []*Cache[K, V]
+	mask   uint64
+	seed   maphash.Seed
+	hash   Hasher[K] // nil for maphash
+	count  int       // requested number of shards; 0 for the default
+
+	opts []Option[K, V] // applied to every shard
+}
+
+// Hashes keys to select their shard in a ShardedCache; see WithHasher.
+type Hasher[K comparable] func(key K) uint64
+
+// Configures a ShardedCache.
+type ShardedOption[K comparable, V any] func(*ShardedCache[K, V])
+
+// Applies opts to every shard. Options with a size, such as WithWeigher or WithEventLog, apply to
+// each shard separately.
+func WithShardOptions[K comparable, V any](opts ...Option[K, V]) ShardedOption[K, V] {
+	return func(sc *ShardedCache[K, V]) {
+		sc.opts = append(sc.opts, opts...)
+	}
+}
+
+// Sets the number of shards, which must be a power of two, instead of scaling it to GOMAXPROCS.
+// More shards reduce contention at the cost of less accurate eviction, as each shard evicts on its own.
+func WithShardCount[K comparable, V any](n int) ShardedOption[K, V] {
+	if n <= 0 || n&(n-1) != 0 {
+		panic("shard count must be a power of two")
+	}
+	return func(sc *ShardedCache[K, V]) {
+		sc.count = n
+	}
+}
+
+// Selects shards with hash instead of the runtime hash of the keys (hash/maphash), e.g. xxhash for
+// long strings, or a hash of the relevant fields of struct keys. The shard is selected by the low bits
+// of the hash, which must therefore be well distributed.
+func WithHasher[K comparable, V any](hash Hasher[K]) ShardedOption[K, V] {
+	if hash == nil {
+		panic("hasher must not be nil")
+	}
+	return func(sc *ShardedCache[K, V]) {
+		sc.hash = hash
+	}
+}
+
+// Creates a sharded cache holding about capacity entries in total. The number of shards is a power
+// of two scaled to GOMAXPROCS unless set by WithShardCount, and at most capacity; each shard holds
+// capacity divided by the number of shards, rounded up.
+func NewShardedCache[K comparable, V any](capacity int, opts ...ShardedOption[K, V]) *ShardedCache[K, V] {
+	if capacity <= 0 {
+		panic("capacity must be greater than zero")
+	}
+	sc := &ShardedCache[K, V]{seed: maphash.MakeSeed()}
+	for _, opt := range opts {
+		opt(sc)
+	}
+	n := shardCount(sc.count, capacity)
+	sc.shards = make([]*Cache[K, V], n)
+	for i := range sc.shards {
+		sc.shards[i] = NewCache((capacity+n-1)/n, sc.opts...)
+	}
+	sc.mask = uint64(n - 1)
+	return sc
+}
+
+// Returns the number of shards for a capacity: count, or the default if it is 0, limited to capacity.
+func shardCount(count, capacity int) int {
+	if count == 0 {
+		count = defaultStripes()
+	}
+	return min(count, 1<<(bits.Len(uint(capacity))-1))
+}
+
+// Default number of shards, and of read buffer stripes: the smallest power of two at least 4 times
+// GOMAXPROCS.
+func defaultStripes() int {
+	return 1 << bits.Len(uint(4*runtime.GOMAXPROCS(0)-1))
+}
+
+// Returns the shard holding key, e.g. to call methods ShardedCache does not expose.
+func (sc *ShardedCache[K, V]) Shard(key K) *Cache[K, V] {
+	if sc.hash != nil {
+		return sc.shards[sc.hash(key)&sc.mask]
+	}
+	return sc.shards[maphash.Comparable(sc.seed, key)&sc.mask]
+}
+
+// Retrieves the value associated with the given key; see Cache.Get.
+func (sc *ShardedCache[K, V]) Get(key K) (V, bool) {
+	return sc.Shard(key).Get(key)
+}
+
+// Retrieves the value associated with the given key without promoting it; see Cache.Peek.
+func (sc *ShardedCache[K, V]) Peek(key K) (V, bool) {
+	return sc.Shard(key).Peek(key)
+}
+
+// Reports whether a live entry exists for key; see Cache.Contains.
+func (sc *ShardedCache[K, V]) Contains(key K) bool {
+	return sc.Shard(key).Contains(key)
+}
+
+// Inserts or updates a key-value pair; see Cache.Set.
+func (sc *ShardedCache[K, V]) Set(key K, value V) {
+	sc.Shard(key).Set(key, value)
+}
+
+// Inserts or updates a key-value pair with an optional TTL; see Cache.SetWithTTL.
+func (sc *ShardedCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	sc.Shard(key).SetWithTTL(key, value, ttl)
+}
+
+// Retrieves the value for key, computing it once for concurrent callers if it is missing; see
+// Cache.GetOrCompute.
+func (sc *ShardedCache[K, V]) GetOrCompute(key K, compute func() (V, error)) (V, error) {
+	return sc.Shard(key).GetOrCompute(key, compute)
+}
+
+// Removes a key from the cache.
+func (sc *ShardedCache[K, V]) Delete(key K) {
+	sc.Shard(key).Delete(key)
+}
+
+// Removes several keys with a single lock acquisition per shard, like Cache.DeleteMany, and returns
+// how many live entries were removed.
+func (sc *ShardedCache[K, V]) DeleteMany(keys []K) int {
+	groups := make(map[*Cache[K, V]][]K)
+	for _, key := range keys {
+		shard := sc.Shard(key)
+		groups[shard] = append(groups[shard], key)
+	}
+	removed := 0
+	for shard, keys := range groups {
+		removed += shard.DeleteMany(keys)
+	}
+	return removed
+}
+
+// Returns the number of live entries across the shards. Shards are counted one after the other, so
+// the result is not a consistent snapshot under concurrent writes.
+func (sc *ShardedCache[K, V]) Len() int {
+	n := 0
+	for _, shard := range sc.shards {
+		n += shard.Len()
+	}
+	return n
+}
+
+// Returns the total capacity of the shards, which may exceed the capacity given to NewShardedCache
+// by rounding.
+func (sc *ShardedCache[K, V]) Capacity() int {
+	n := 0
+	for _, shard := range sc.shards {
+		n += shard.Capacity()
+	}
+	return n
+}
+
+// Returns the number of shards.
+func (sc *ShardedCache[K, V]) Shards() int {
+	return len(sc.shards)
+}
+
+// Returns the counters summed over the shards, except MaxWaiters and Contention.MaxWait, which are the
+// maximum over the shards. Settings, such as Promotion and Config, are those of the first shard,
+// except Config.Capacity, which is the total capacity.
+func (sc *ShardedCache[K, V]) Stats() Stats {
+	var total Stats
+	for i, shard := range sc.shards {
+		stats := shard.Stats()
+		if i == 0 {
+			total = stats
+			continue
+		}
+		total.Hits += stats.Hits
+		total.Misses += stats.Misses
+		total.Promotions += stats.Promotions
+		total.Coalesced += stats.Coalesced
+		total.Expirations += stats.Expirations
+		total.Evictions += stats.Evictions
+		total.Size += stats.Size
+		total.Tombstones += stats.Tombstones
+		total.Weight += stats.Weight
+		total.DroppedEvents += stats.DroppedEvents
+		total.Flights += stats.Flights
+		total.Waiters += stats.Waiters
+		total.MaxWaiters = max(total.MaxWaiters, stats.MaxWaiters)
+		total.Contention.Acquisitions += stats.Contention.Acquisitions
+		total.Contention.Contended += stats.Contention.Contended
+		total.Contention.TotalWait += stats.Contention.TotalWait
+		total.Contention.MaxWait = max(total.Contention.MaxWait, stats.Contention.MaxWait)
+		total.Config.Capacity += stats.Config.Capacity
+		total.Config.MaxWeight += stats.Config.MaxWeight
+		total.Config.MaxMemory += stats.Config.MaxMemory
+	}
+	total.Config.Shards = len(sc.shards)
+	return total
+}
+
+// Clears all entries from every shard.
+func (sc *ShardedCache[K, V]) Dump() {
+	for _, shard := range sc.shards {
+		shard.Dump()
+	}
+}
+
+// Stops the background goroutines of every shard.
+func (sc *ShardedCache[K, V]) Close() {
+	for _, shard := range sc.shards {
+		shard.Close()
+	}
+}
@@ -0,0 +1,116 @@
+package goutte_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestShardedCache(t *testing.T) {
+	cache := goutte.NewShardedCache[string, int](1000)
+	defer cache.Close()
+
+	if n := cache.Shards(); n&(n-1) != 0 {
+		t.Errorf("Expected a power of two shards, got %d", n)
+	}
+	if c := cache.Capacity(); c < 1000 || c >= 1000+cache.Shards() {
+		t.Errorf("Expected a total capacity of about 1000, got %d", c)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				key := fmt.Sprintf("key-%d", i)
+				cache.Set(key, i)
+				if val, ok := cache.Get(key); ok && val != i {
+					t.Errorf("Expected %q to be %d, got %d", key, i, val)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := cache.Len(); n != 100 {
+		t.Errorf("Expected 100 entries, got %d", n)
+	}
+	cache.Delete("key-0")
+	if cache.Contains("key-0") {
+		t.Error("Expected 'key-0' to be deleted")
+	}
+	if stats := cache.Stats(); stats.Hits != 800 || stats.Size != 99 {
+		t.Errorf("Expected 800 hits and 99 entries, got %+v", stats)
+	}
+
+	// The shard of a key exposes the rest of the Cache API.
+	cache.Shard("key-1").Touch("key-1")
+}
+
+func TestShardedCacheEviction(t *testing.T) {
+	cache := goutte.NewShardedCache(2, goutte.WithShardOptions(goutte.WithPolicy[int, int](goutte.PolicyFIFO())))
+	defer cache.Close()
+
+	if n := cache.Shards(); n > 2 {
+		t.Errorf("Expected at most 2 shards for a capacity of 2, got %d", n)
+	}
+	for i := 0; i < 100; i++ {
+		cache.Set(i, i)
+	}
+	if n := cache.Len(); n > cache.Capacity() {
+		t.Errorf("Expected at most %d entries, got %d", cache.Capacity(), n)
+	}
+	if p := cache.Stats().Policy; p != "fifo" {
+		t.Errorf("Expected the shard options to apply, got policy %q", p)
+	}
+}
+
+func TestShardedCacheDeleteMany(t *testing.T) {
+	cache := goutte.NewShardedCache(100, goutte.WithShardCount[int, int](4))
+	defer cache.Close()
+	for i := 0; i < 20; i++ {
+		cache.Set(i, i)
+	}
+
+	if n := cache.DeleteMany([]int{0, 1, 2, 3, 4, 5, 50}); n != 6 {
+		t.Errorf("Expected DeleteMany to remove 6 keys, got %d", n)
+	}
+	if n := cache.Len(); n != 14 {
+		t.Errorf("Expected 14 entries left, got %d", n)
+	}
+	if cache.Contains(3) || !cache.Contains(6) {
+		t.Error("Expected only the given keys to be deleted")
+	}
+}
+
+func TestShardedCacheShardCountAndHasher(t *testing.T) {
+	type point struct{ x, y int }
+	cache := goutte.NewShardedCache(64,
+		goutte.WithShardCount[point, string](4),
+		goutte.WithHasher[point, string](func(p point) uint64 { return uint64(p.x) }))
+	defer cache.Close()
+
+	if n := cache.Shards(); n != 4 {
+		t.Fatalf("Expected 4 shards, got %d", n)
+	}
+	cache.Set(point{1, 2}, "a")
+	if cache.Shard(point{1, 2}) != cache.Shard(point{5, 7}) {
+		t.Error("Expected keys hashing to the same low bits to share a shard")
+	}
+	if cache.Shard(point{1, 2}) == cache.Shard(point{2, 2}) {
+		t.Error("Expected keys hashing to different low bits to use different shards")
+	}
+	if val, ok := cache.Get(point{1, 2}); !ok || val != "a" {
+		t.Errorf("Expected 'a', got %q (found: %v)", val, ok)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a shard count that is not a power of two to panic")
+		}
+	}()
+	goutte.WithShardCount[int, int](3)
+}
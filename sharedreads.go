@@ -0,0 +1,76 @@
+package goutte
+
+// Lets Get, Peek and Contains serve live entries under a shared read lock, so concurrent reads no
+// longer serialize on the cache mutex. A hit then only marks the entry as accessed instead of
+// promoting it: a marked entry is promoted as the eviction policy promotes hits once it becomes the
+// next eviction candidate, just before a victim is chosen, giving it a second chance as in
+// PolicyClock. LRU ordering, and the ordered views such as Snapshot, become approximate, and promotion
+// strategies do not apply to these hits.
+//
+// Reads fall back to the exclusive lock when they need to update more than the access mark: misses,
+// expired or soft-deleted entries, sliding TTLs, hit-streak TTLs and refresh-ahead, caches with
+// WithRankHistogram, WithKeyStats, WithPartitions or warm-up tracking enabled, and PolicyMRU, whose
+// victim depends on the order of the hits.
+func WithSharedReads[K comparable, V any]() Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.sharedReads = true
+	}
+}
+
+// Looks up a live entry under the read lock, recording the access and counting a hit if hit is set.
+// It reports false if the key is not found, or if the read needs the exclusive lock.
+func (c *Cache[K, V]) getShared(key K, hit bool) (V, bool) {
+	c.rlock()
+	val, ok, drain := c.getSharedLocked(key, hit)
+	c.mu.RUnlock()
+	if drain {
+		c.tryDrainReads()
+	}
+	return val, ok
+}
+
+// Implements getShared with the read lock held, also reporting whether the read buffer should be
+// drained.
+func (c *Cache[K, V]) getSharedLocked(key K, hit bool) (_ V, _ bool, drain bool) {
+	var zero V
+	if hit && (c.policy.kind == policyMRU || c.ranks != nil || c.keyStats != nil || c.partitions != nil || c.warmup != nil) {
+		return zero, false, false
+	}
+	ele, ok := c.cache[key]
+	if !ok {
+		return zero, false, false
+	}
+	ent := ele.Value.(*entry[K, V])
+	if ent.tombstone || (!ent.expiration.IsZero() && c.clock.Now().After(ent.expiration)) {
+		return zero, false, false
+	}
+	if hit {
+		if ent.sliding || ent.streak != nil || !ent.refresh.IsZero() {
+			return zero, false, false
+		}
+		if c.readBuf != nil {
+			drain = c.readBuf.record(ele)
+		} else if !ent.accessed.Load() {
+			ent.accessed.Store(true)
+		}
+		c.sharedHits.Add(1)
+	}
+	return ent.value, true, drain
+}
+
+// Promotes the eviction candidates of the policy while they are marked by shared reads, until the
+// candidate is unmarked or the whole list has been examined. Policies that keep hit entries in place,
+// such as FIFO or the admission queue of 2Q, only have the mark cleared.
+func (c *Cache[K, V]) applySharedHitsLocked() {
+	for n := c.ll.Len(); n > 0; n-- {
+		ele := c.candidateLocked()
+		ent := ele.Value.(*entry[K, V])
+		if !ent.accessed.Load() {
+			return
+		}
+		ent.accessed.Store(false)
+		if c.promoteLocked(ele) {
+			c.promotions++
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package goutte_test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheSharedReads(t *testing.T) {
+	cache := goutte.NewCache(2, goutte.WithSharedReads[string, int]())
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+
+	if val, ok := cache.Get("a"); !ok || val != 1 {
+		t.Errorf("Expected key 'a' to have value 1, got %v (found: %v)", val, ok)
+	}
+	// The hit on 'a' is applied when it reaches the back of the list, so 'b' is evicted.
+	cache.Set("c", 3)
+	if cache.Contains("b") || !cache.Contains("a") {
+		t.Error("Expected 'b' to be evicted and 'a' to be kept")
+	}
+
+	cache.SetWithTTL("d", 4, 10*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := cache.Get("d"); ok {
+		t.Error("Expected 'd' to have expired")
+	}
+	if _, ok := cache.Peek("missing"); ok {
+		t.Error("Expected a missing key not to be found")
+	}
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Misses != 1 || stats.Promotions != 1 {
+		t.Errorf("Expected 1 hit, 1 miss and 1 promotion, got %+v", stats)
+	}
+	if stats := cache.ResetStats(); stats.Hits != 1 {
+		t.Errorf("Expected ResetStats to report 1 hit, got %d", stats.Hits)
+	}
+	if hits := cache.Stats().Hits; hits != 0 {
+		t.Errorf("Expected no hit after the reset, got %d", hits)
+	}
+}
+
+func TestCacheSharedReadsConcurrency(t *testing.T) {
+	cache := goutte.NewCache(64, goutte.WithSharedReads[int, int]())
+	defer cache.Close()
+	for i := 0; i < 64; i++ {
+		cache.Set(i, i)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := (g*31 + i) % 128
+				if val, ok := cache.Get(key); ok && val != key {
+					t.Errorf("Expected key %d to have value %d, got %d", key, key, val)
+				}
+				if i%10 == 0 {
+					cache.Set(key, key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := cache.Len(); n != 64 {
+		t.Errorf("Expected 64 entries, got %d", n)
+	}
+}
+
+func TestCacheSharedReadsPolicies(t *testing.T) {
+	policies := []goutte.Policy{
+		goutte.PolicyLRU(),
+		goutte.Policy2Q(0.25, 0.5),
+		goutte.PolicySLRU(0.5),
+		goutte.PolicyClock(),
+		goutte.PolicyFIFO(),
+		goutte.PolicyMRU(),
+	}
+	// Writes only, so both caches start from the same state: rewritten and re-admitted keys fill the
+	// protected segments of 2Q and SLRU, and set the reference bits of PolicyClock.
+	prefix := func(cache *goutte.Cache[int, int]) {
+		for _, key := range []int{0, 1, 2, 3, 4, 5, 6, 7, 0, 1, 9, 2, 3, 10, 11, 4, 0, 1, 12} {
+			cache.Set(key, key)
+		}
+	}
+	for _, p := range policies {
+		t.Run(p.String(), func(t *testing.T) {
+			for read := range 12 * 12 {
+				exclusive := goutte.NewCache(8, goutte.WithPolicy[int, int](p))
+				shared := goutte.NewCache(8, goutte.WithPolicy[int, int](p), goutte.WithSharedReads[int, int]())
+				prefix(exclusive)
+				prefix(shared)
+
+				// Reads just before an eviction affect it as they would without shared reads.
+				for _, cache := range []*goutte.Cache[int, int]{exclusive, shared} {
+					cache.Get(read / 12)
+					cache.Get(read % 12)
+					cache.Set(100, 100)
+				}
+				want, got := exclusive.Items(), shared.Items()
+				if len(got) != len(want) {
+					t.Errorf("After reading %d and %d, expected entries %v, got %v", read/12, read%12, want, got)
+				}
+				for key := range want {
+					if _, ok := got[key]; !ok {
+						t.Errorf("After reading %d and %d, expected entries %v, got %v", read/12, read%12, want, got)
+						break
+					}
+				}
+				exclusive.Close()
+				shared.Close()
+			}
+		})
+	}
+}
+
+func TestCacheSharedReads2Q(t *testing.T) {
+	cache := goutte.NewCache(4,
+		goutte.WithPolicy[int, int](goutte.Policy2Q(0.75, 1)),
+		goutte.WithSharedReads[int, int]())
+	defer cache.Close()
+	for _, key := range []int{0, 1, 2, 3, 4, 0} {
+		cache.Set(key, key) // 0 and 1 are evicted from the admission queue, and 0 comes back to Am
+	}
+	cache.Delete(2)
+	cache.Set(1, 1) // back to Am too, which now holds 1 and 0, with 3 and 4 in the admission queue
+
+	// With the admission queue within its share, the least recently used entry of Am is evicted,
+	// and the read spares 0.
+	cache.Get(0)
+	cache.Set(100, 100)
+	if cache.Contains(1) || !cache.Contains(0) {
+		t.Errorf("Expected the read of 0 to evict 1 instead, got %v", slices.Collect(cache.Keys()))
+	}
+}
@@ -0,0 +1,134 @@
+package goutte
+
+import (
+	"iter"
+	"time"
+)
+
+// Point-in-time copy of a live entry, as returned by Snapshot.
+type SnapshotEntry[K comparable, V any] struct {
+	Key        K
+	Value      V
+	Expiration time.Time // zero if the entry has no TTL
+}
+
+// Returns copies of all live entries, ordered from least to most recently used, without affecting recency.
+// Re-inserting the entries in order into another cache reproduces the same LRU ordering.
+func (c *Cache[K, V]) Snapshot() []SnapshotEntry[K, V] {
+	c.lock()
+	defer c.unlock()
+
+	entries := make([]SnapshotEntry[K, V], 0, c.ll.Len())
+	c.forEachLiveLocked(func(ent *entry[K, V]) {
+		entries = append(entries, SnapshotEntry[K, V]{Key: ent.key, Value: ent.value, Expiration: ent.expiration})
+	})
+	return entries
+}
+
+// Returns a copy of the least recently used live entry, the next eviction candidate, without affecting
+// recency. The second result is false if the cache holds no live entry.
+func (c *Cache[K, V]) Oldest() (SnapshotEntry[K, V], bool) {
+	c.lock()
+	defer c.unlock()
+
+	return c.endEntryLocked(false)
+}
+
+// Returns a copy of the most recently used live entry without affecting recency.
+// The second result is false if the cache holds no live entry.
+func (c *Cache[K, V]) Newest() (SnapshotEntry[K, V], bool) {
+	c.lock()
+	defer c.unlock()
+
+	return c.endEntryLocked(true)
+}
+
+func (c *Cache[K, V]) endEntryLocked(front bool) (SnapshotEntry[K, V], bool) {
+	ele := c.liveEndLocked(front)
+	if ele == nil {
+		return SnapshotEntry[K, V]{}, false
+	}
+	ent := ele.Value.(*entry[K, V])
+	return SnapshotEntry[K, V]{Key: ent.key, Value: ent.value, Expiration: ent.expiration}, true
+}
+
+// Returns an iterator over the keys of all live entries, from least to most recently used, without
+// affecting recency. The keys are copied under the lock when iteration starts, so the loop body may
+// call back into the cache.
+func (c *Cache[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		c.lock()
+		keys := make([]K, 0, c.ll.Len())
+		c.forEachLiveLocked(func(ent *entry[K, V]) {
+			keys = append(keys, ent.key)
+		})
+		c.unlock()
+
+		for _, key := range keys {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Returns an iterator over all live entries, from least to most recently used, without affecting
+// recency. Like Keys, it iterates over a snapshot taken when iteration starts.
+func (c *Cache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, e := range c.Snapshot() {
+			if !yield(e.Key, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Returns a copy of all live entries keyed by key, without affecting recency.
+func (c *Cache[K, V]) Items() map[K]V {
+	c.lock()
+	defer c.unlock()
+
+	items := make(map[K]V, c.ll.Len())
+	c.forEachLiveLocked(func(ent *entry[K, V]) {
+		items[ent.key] = ent.value
+	})
+	return items
+}
+
+// Returns the values of all live entries, ordered from least to most recently used, without affecting recency.
+func (c *Cache[K, V]) Values() []V {
+	c.lock()
+	defer c.unlock()
+
+	values := make([]V, 0, c.ll.Len())
+	c.forEachLiveLocked(func(ent *entry[K, V]) {
+		values = append(values, ent.value)
+	})
+	return values
+}
+
+// Calls fn for each live entry, from most to least recently used, until fn returns false, without
+// affecting recency. The entries are copied under the lock first and fn runs without it, so fn may
+// call back into the cache, e.g. to delete the entries it selects; it sees the entries as they were
+// when Range was called.
+func (c *Cache[K, V]) Range(fn func(key K, value V) bool) {
+	entries := c.Snapshot()
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !fn(entries[i].Key, entries[i].Value) {
+			return
+		}
+	}
+}
+
+// Calls fn for each live entry, from least to most recently used, skipping expired and soft-deleted ones.
+func (c *Cache[K, V]) forEachLiveLocked(fn func(ent *entry[K, V])) {
+	now := c.clock.Now()
+	for ele := c.ll.Back(); ele != nil; ele = ele.Prev() {
+		ent := ele.Value.(*entry[K, V])
+		if ent.tombstone || (!ent.expiration.IsZero() && now.After(ent.expiration)) {
+			continue
+		}
+		fn(ent)
+	}
+}
@@ -0,0 +1,156 @@
+package goutte_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheSnapshot(t *testing.T) {
+	cache := goutte.NewCache[string, int](3)
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.SetWithTTL("b", 2, time.Minute)
+	cache.Set("c", 3)
+	cache.Get("a")
+	cache.SoftDelete("c", time.Minute)
+
+	entries := cache.Snapshot()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 live entries, got %+v", entries)
+	}
+	// Least recently used first; the soft-deleted key is skipped.
+	if entries[0].Key != "b" || entries[0].Value != 2 || entries[0].Expiration.IsZero() {
+		t.Errorf("Expected 'b' with a TTL first, got %+v", entries[0])
+	}
+	if entries[1].Key != "a" || entries[1].Value != 1 || !entries[1].Expiration.IsZero() {
+		t.Errorf("Expected 'a' without a TTL last, got %+v", entries[1])
+	}
+
+	// Snapshot does not promote: 'b' is still the eviction candidate.
+	cache.Set("d", 4)
+	cache.Set("e", 5)
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected key 'b' to be evicted")
+	}
+}
+
+func TestCacheKeys(t *testing.T) {
+	cache := goutte.NewCache[string, int](3)
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.SetWithTTL("b", 2, 20*time.Millisecond)
+	cache.Set("c", 3)
+	cache.Get("a")
+
+	time.Sleep(50 * time.Millisecond)
+	keys := slices.Collect(cache.Keys())
+	if len(keys) != 2 || keys[0] != "c" || keys[1] != "a" {
+		t.Errorf("Expected live keys [c a], got %v", keys)
+	}
+}
+
+func TestCacheItemsValues(t *testing.T) {
+	cache := goutte.NewCache[string, int](3)
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	cache.SoftDelete("b", time.Minute)
+	cache.Get("a")
+
+	items := cache.Items()
+	if len(items) != 2 || items["a"] != 1 || items["c"] != 3 {
+		t.Errorf("Expected items map[a:1 c:3], got %v", items)
+	}
+	values := cache.Values()
+	if len(values) != 2 || values[0] != 3 || values[1] != 1 {
+		t.Errorf("Expected values [3 1], got %v", values)
+	}
+}
+
+func TestCacheRange(t *testing.T) {
+	cache := goutte.NewCache[string, int](4)
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	cache.Set("d", 4)
+
+	// Most recently used first, stopping early; deleting from fn does not deadlock.
+	var visited []string
+	cache.Range(func(k string, v int) bool {
+		visited = append(visited, k)
+		if v%2 == 0 {
+			cache.Delete(k)
+		}
+		return k != "b"
+	})
+	if len(visited) != 3 || visited[0] != "d" || visited[1] != "c" || visited[2] != "b" {
+		t.Errorf("Expected to visit [d c b], got %v", visited)
+	}
+	if keys := slices.Collect(cache.Keys()); len(keys) != 2 || keys[0] != "a" || keys[1] != "c" {
+		t.Errorf("Expected keys [a c] after purging even values, got %v", keys)
+	}
+}
+
+func TestCacheAll(t *testing.T) {
+	cache := goutte.NewCache[string, int](3)
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+
+	// Mutating the cache inside the loop does not deadlock.
+	var visited []string
+	for k, v := range cache.All() {
+		visited = append(visited, k)
+		cache.Set(k, v*10)
+		if k == "b" {
+			break
+		}
+	}
+	if len(visited) != 2 || visited[0] != "a" || visited[1] != "b" {
+		t.Errorf("Expected to visit [a b], got %v", visited)
+	}
+	if val, _ := cache.Get("b"); val != 20 {
+		t.Errorf("Expected 'b' to be updated to 20, got %d", val)
+	}
+
+	var keys []string
+	for k := range cache.Keys() {
+		cache.Delete(k)
+		keys = append(keys, k)
+	}
+	if len(keys) != 3 || cache.Len() != 0 {
+		t.Errorf("Expected to visit and delete 3 keys, got %v (remaining: %d)", keys, cache.Len())
+	}
+}
+
+func TestCacheOldestNewest(t *testing.T) {
+	cache := goutte.NewCache[string, int](3)
+	defer cache.Close()
+
+	if _, ok := cache.Oldest(); ok {
+		t.Error("Expected no oldest entry in an empty cache")
+	}
+
+	cache.Set("a", 1)
+	cache.SetWithTTL("b", 2, time.Minute)
+	cache.Set("c", 3)
+	cache.Get("a")
+
+	if e, ok := cache.Oldest(); !ok || e.Key != "b" || e.Value != 2 || e.Expiration.IsZero() {
+		t.Errorf("Expected 'b' with a TTL to be the oldest, got %+v (found: %v)", e, ok)
+	}
+	if e, ok := cache.Newest(); !ok || e.Key != "a" || e.Value != 1 {
+		t.Errorf("Expected 'a' to be the newest, got %+v (found: %v)", e, ok)
+	}
+
+	// Peeking at the ends does not change the order.
+	if e, _ := cache.Oldest(); e.Key != "b" {
+		t.Errorf("Expected 'b' to still be the oldest, got %q", e.Key)
+	}
+}
@@ -0,0 +1,93 @@
+package goutte
+
+// Point-in-time copy of the cache counters, as returned by Stats.
+type Stats struct {
+	Hits        uint64 // lookups that found a live entry
+	Misses      uint64 // lookups that found nothing
+	Promotions  uint64 // hits that moved the entry to the front of the list
+	Promotion   string // promotion strategy, see WithPromotion
+	Policy      string // eviction policy, see WithPolicy
+	Coalesced   uint64 // misses served by another caller's computation, see GetOrCompute
+	Expirations uint64 // entries removed because their TTL elapsed
+	Evictions   uint64 // entries evicted to respect the capacity
+	Size        int    // live entries, as reported by Len
+	Tombstones  int    // soft-deleted entries awaiting the end of their window, see SoftDelete
+	Weight      int64  // total weight of the entries, see WithWeigher and SetWithCost
+
+	DroppedEvents uint64 // events dropped by full subscription queues, see WithSubscriberQueue
+
+	// Computations in progress, such as those of GetOrCompute and refresh-ahead loads, and callers
+	// currently blocked waiting for them, in total and on the most awaited key. A stampede shows up as
+	// a high MaxWaiters, and Coalesced counts the callers it spared a computation.
+	Flights    int
+	Waiters    int
+	MaxWaiters int
+
+	// Lock wait counters, as returned by ContentionStats; zero unless the package is built with the
+	// goutte_contention tag.
+	Contention ContentionStats
+
+	// Progress of the running migration, see Migrate; zero if none is running.
+	Migration MigrationStats
+
+	// Effective settings, whether the cache was created by NewFromConfig or by NewCache with options.
+	// Promotion and the janitor settings are only set for caches created or reconfigured from a Config;
+	// the Promotion field of Stats reports the strategy in use either way.
+	Config Config
+}
+
+// Returns a snapshot of the cache counters.
+func (c *Cache[K, V]) Stats() Stats {
+	c.lock()
+	defer c.unlock()
+
+	return c.statsLocked(c.sharedHits.Load())
+}
+
+// Returns a snapshot of the cache counters like Stats, then zeroes the counters, atomically, so
+// successive calls report per-interval figures, including the contention counters. Size, Tombstones,
+// Weight, Flights, Waiters, MaxWaiters, Promotion, Policy, Migration and Config are not counters and
+// are unaffected.
+func (c *Cache[K, V]) ResetStats() Stats {
+	c.lock()
+	defer c.unlock()
+
+	stats := c.statsLocked(c.sharedHits.Swap(0))
+	c.hits, c.misses, c.promotions, c.coalesced = 0, 0, 0, 0
+	c.expirations, c.evictions = 0, 0
+	c.droppedEvents = 0
+	c.contention.reset()
+	return stats
+}
+
+// Returns the counters, with sharedHits hits served under the read lock.
+func (c *Cache[K, V]) statsLocked(sharedHits uint64) Stats {
+	dueLive, dueTombstones := c.countDueLocked(c.clock.Now())
+	stats := Stats{
+		Hits:        c.hits + sharedHits,
+		Misses:      c.misses,
+		Promotions:  c.promotions,
+		Promotion:   c.promotion.String(),
+		Policy:      c.policy.String(),
+		Coalesced:   c.coalesced,
+		Expirations: c.expirations,
+		Evictions:   c.evictions,
+		Size:        c.ll.Len() - c.tombstones - dueLive,
+		Tombstones:  c.tombstones - dueTombstones,
+		Weight:      c.weight,
+		Config:      c.effectiveConfigLocked(),
+
+		DroppedEvents: c.droppedEvents,
+		Flights:       len(c.flights),
+		Contention:    c.ContentionStats(),
+	}
+	for _, f := range c.flights {
+		n := int(f.waiters.Load())
+		stats.Waiters += n
+		stats.MaxWaiters = max(stats.MaxWaiters, n)
+	}
+	if c.migration != nil {
+		stats.Migration = c.migration.stats
+	}
+	return stats
+}
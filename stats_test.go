@@ -0,0 +1,155 @@
+package goutte_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheStats(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+
+	cache.SetWithTTL("a", 1, 20*time.Millisecond)
+	cache.Set("b", 2)
+	cache.Set("c", 3) // evicts "a"
+	cache.SetWithTTL("d", 4, 20*time.Millisecond)
+	cache.Get("c")
+	cache.Get("a")
+	time.Sleep(50 * time.Millisecond)
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss, got %+v", stats)
+	}
+	if stats.Evictions != 2 || stats.Expirations != 1 {
+		t.Errorf("Expected 2 capacity evictions and 1 expiration, got %+v", stats)
+	}
+	if stats.Size != 1 {
+		t.Errorf("Expected a size of 1, got %d", stats.Size)
+	}
+}
+
+func TestCacheResetStats(t *testing.T) {
+	cache := goutte.NewCache[string, int](1)
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Get("b")
+	cache.Get("a")
+
+	if stats := cache.ResetStats(); stats.Hits != 1 || stats.Misses != 1 || stats.Evictions != 1 {
+		t.Errorf("Expected ResetStats to return the counters so far, got %+v", stats)
+	}
+	cache.Get("b")
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 0 || stats.Evictions != 0 || stats.Promotions != 1 {
+		t.Errorf("Expected only the hit since the reset, got %+v", stats)
+	}
+	if stats.Size != 1 {
+		t.Errorf("Expected the size to be unaffected, got %d", stats.Size)
+	}
+}
+
+func TestCacheStatsDoNotExpire(t *testing.T) {
+	for _, wheel := range []bool{false, true} {
+		t.Run(fmt.Sprintf("wheel=%v", wheel), func(t *testing.T) {
+			clock := goutte.NewManualClock(time.Unix(0, 0))
+			started := make(chan struct{})
+			release := make(chan struct{})
+			var mu sync.Mutex
+			removed := 0
+			first := true
+			opts := []goutte.Option[int, int]{
+				goutte.WithClock[int, int](clock),
+				goutte.WithJanitor[int, int](goutte.JanitorConfig{BatchSize: 1}),
+				goutte.WithOnRemoval(func(int, int, goutte.EvictionReason) {
+					mu.Lock()
+					if first {
+						// Hold the expiration goroutine after its first batch.
+						first = false
+						mu.Unlock()
+						close(started)
+						<-release
+						return
+					}
+					removed++
+					mu.Unlock()
+				}),
+			}
+			if wheel {
+				opts = append(opts, goutte.WithTimingWheel[int, int](time.Millisecond))
+			}
+			cache := goutte.NewCache(10, opts...)
+			defer cache.Close()
+			defer close(release)
+
+			for i := 1; i <= 3; i++ {
+				cache.SetWithTTL(i, i, time.Duration(i)*time.Second)
+			}
+			cache.Set(4, 4)
+			cache.SoftDelete(4, time.Second)
+			clock.Advance(time.Minute)
+			<-started
+
+			if n := cache.Len(); n != 0 {
+				t.Errorf("Expected no live entries, got %d", n)
+			}
+			if stats := cache.Stats(); stats.Size != 0 || stats.Tombstones != 0 || stats.Expirations != 1 {
+				t.Errorf("Expected no live entries or tombstones and 1 expiration, got %+v", stats)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if removed != 0 {
+				t.Errorf("Expected Len and Stats not to remove entries, got %d removals", removed)
+			}
+		})
+	}
+}
+
+func TestCacheStatsFlights(t *testing.T) {
+	cache := goutte.NewCache[string, int](10)
+	defer cache.Close()
+	release := make(chan struct{})
+	compute := func() (int, error) {
+		<-release
+		return 1, nil
+	}
+
+	// One caller computes each key, and the others wait for it.
+	var wg sync.WaitGroup
+	for _, key := range []string{"a", "a", "a", "a", "b"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.GetOrCompute(key, compute)
+		}()
+	}
+	waitStats := func(ok func(goutte.Stats) bool) goutte.Stats {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			stats := cache.Stats()
+			if ok(stats) || time.Now().After(deadline) {
+				return stats
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	stats := waitStats(func(s goutte.Stats) bool { return s.Waiters == 3 && s.Flights == 2 })
+	if stats.Flights != 2 || stats.Waiters != 3 || stats.MaxWaiters != 3 {
+		t.Errorf("Expected 2 flights and 3 waiters on one key, got %d flights, %d waiters, %d at most",
+			stats.Flights, stats.Waiters, stats.MaxWaiters)
+	}
+
+	close(release)
+	wg.Wait()
+	if stats := cache.Stats(); stats.Flights != 0 || stats.Waiters != 0 || stats.MaxWaiters != 0 {
+		t.Errorf("Expected no flights left, got %d flights, %d waiters, %d at most",
+			stats.Flights, stats.Waiters, stats.MaxWaiters)
+	}
+}
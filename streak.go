@@ -0,0 +1,82 @@
+package goutte
+
+import "time"
+
+// Policy adapting the TTL of entries to how often they are read; see WithHitStreakTTL.
+// It only applies to entries written with a TTL.
+type HitStreakTTL struct {
+	// Number of hits within an entry's lifetime after which each further hit extends its expiration
+	// to at least Extension from now.
+	MinHits   int
+	Extension time.Duration
+
+	// Upper bound on an entry's lifetime, measured from its last write, that extensions cannot exceed.
+	MaxLifetime time.Duration
+
+	// If positive, entries start with at most this TTL and only receive the TTL they were written with
+	// on their first hit, so entries that are never re-read leave the cache early. 0 disables.
+	ColdTTL time.Duration
+}
+
+// Per-entry bookkeeping for HitStreakTTL.
+type streakState struct {
+	hits    int
+	written time.Time
+	ttl     time.Duration // TTL requested by the last write
+}
+
+// Extends the TTL (up to a cap) of entries read often and, optionally, shortens the TTL of entries
+// never re-read. Hit counts restart whenever an entry is written. Expirations are updated in the
+// expiration heap, so the janitor removes entries at their adapted deadline.
+func WithHitStreakTTL[K comparable, V any](p HitStreakTTL) Option[K, V] {
+	if p.MinHits <= 0 || p.Extension <= 0 || p.MaxLifetime <= 0 || p.ColdTTL < 0 {
+		panic("hit streak TTL requires positive MinHits, Extension and MaxLifetime")
+	}
+	return func(c *Cache[K, V]) {
+		c.hitStreak = &p
+	}
+}
+
+// Resets the streak of a written entry and returns the expiration to apply.
+func (c *Cache[K, V]) streakWriteLocked(ent *entry[K, V], expiration time.Time) time.Time {
+	if expiration.IsZero() {
+		ent.streak = nil
+		return expiration
+	}
+	now := c.clock.Now()
+	ent.streak = &streakState{written: now, ttl: expiration.Sub(now)}
+	if p := c.hitStreak; p.ColdTTL > 0 && expiration.Sub(now) > p.ColdTTL {
+		return now.Add(p.ColdTTL)
+	}
+	return expiration
+}
+
+// Records a hit on an entry and moves its expiration according to the policy.
+func (c *Cache[K, V]) streakHitLocked(ent *entry[K, V]) {
+	st, p := ent.streak, c.hitStreak
+	st.hits++
+	expiration := ent.expiration
+	if st.hits == 1 && p.ColdTTL > 0 {
+		// The entry proved to be re-read: grant the TTL it was written with.
+		expiration = later(expiration, st.written.Add(st.ttl))
+	}
+	if st.hits > p.MinHits {
+		expiration = later(expiration, c.clock.Now().Add(p.Extension))
+	}
+	if limit := st.written.Add(p.MaxLifetime); expiration.After(limit) {
+		expiration = later(limit, st.written.Add(st.ttl))
+	}
+	if !expiration.Equal(ent.expiration) {
+		c.setExpirationLocked(ent, expiration)
+		if c.migration != nil {
+			c.mirrorLocked(ent.key)
+		}
+	}
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
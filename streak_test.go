@@ -0,0 +1,66 @@
+package goutte_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestHitStreakTTLExtendsHotEntries(t *testing.T) {
+	cache := goutte.NewCache[string, int](4, goutte.WithHitStreakTTL[string, int](goutte.HitStreakTTL{
+		MinHits:     2,
+		Extension:   300 * time.Millisecond,
+		MaxLifetime: 400 * time.Millisecond,
+	}))
+	defer cache.Close()
+
+	cache.SetWithTTL("hot", 1, 100*time.Millisecond)
+	cache.SetWithTTL("cold", 2, 100*time.Millisecond)
+	for i := 0; i < 3; i++ {
+		cache.Get("hot")
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if _, ok := cache.Get("hot"); !ok {
+		t.Error("Expected 'hot' to be kept alive by its hit streak")
+	}
+	if _, ok := cache.Get("cold"); ok {
+		t.Error("Expected 'cold' to expire with its original TTL")
+	}
+
+	// Extensions never go past MaxLifetime.
+	time.Sleep(250 * time.Millisecond)
+	if _, ok := cache.Get("hot"); ok {
+		t.Error("Expected 'hot' to expire once MaxLifetime is reached")
+	}
+}
+
+func TestHitStreakTTLColdEntries(t *testing.T) {
+	cache := goutte.NewCache[string, int](4, goutte.WithHitStreakTTL[string, int](goutte.HitStreakTTL{
+		MinHits:     10,
+		Extension:   time.Second,
+		MaxLifetime: time.Second,
+		ColdTTL:     50 * time.Millisecond,
+	}))
+	defer cache.Close()
+
+	cache.SetWithTTL("read", 1, 300*time.Millisecond)
+	cache.SetWithTTL("unread", 2, 300*time.Millisecond)
+	cache.Get("read")
+
+	time.Sleep(150 * time.Millisecond)
+	if _, ok := cache.Get("read"); !ok {
+		t.Error("Expected 'read' to receive its full TTL after its first hit")
+	}
+	if n := len(cache.Snapshot()); n != 1 {
+		t.Errorf("Expected 'unread' to be removed after ColdTTL, cache holds %d entries", n)
+	}
+
+	// A rewrite restarts the streak.
+	cache.SetWithTTL("read", 3, 300*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	if _, ok := cache.Get("read"); ok {
+		t.Error("Expected the rewritten 'read' to start over with ColdTTL")
+	}
+}
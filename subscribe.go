@@ -0,0 +1,219 @@
+package goutte
+
+import (
+	"slices"
+	"sync"
+)
+
+// Callback registered with Subscribe, or channel returned by Watch, fed by its own goroutine.
+type subscriber[K comparable, V any] struct {
+	fn   func(Event[K], V)
+	out  chan Event[K] // set instead of fn by Watch; closed when the subscription ends
+	wake chan struct{} // signals that queue is not empty
+	stop chan struct{} // closed by cancel
+
+	size     int            // maximum number of queued events
+	overflow OverflowPolicy // what to drop once size events are queued
+
+	mu       sync.Mutex
+	queue    []published[K, V]
+	canceled bool
+}
+
+// Events a subscription drops once its queue is full; see WithSubscriberQueue.
+type OverflowPolicy int
+
+const (
+	// Drop the oldest queued event to make room for the new one, so the subscriber catches up on the
+	// latest state.
+	DropOldest OverflowPolicy = iota
+	// Drop the new event, so the subscriber sees a gapless prefix of the mutations.
+	DropNewest
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case DropOldest:
+		return "drop-oldest"
+	case DropNewest:
+		return "drop-newest"
+	}
+	return "unknown"
+}
+
+// Default maximum number of events queued per subscription.
+const defaultSubscriberQueue = 1024
+
+// Bounds the events queued for each subscription to size, instead of 1024 by default. Once a
+// subscriber falls size events behind, further events are dropped according to overflow and counted
+// in Stats.DroppedEvents, so a slow subscriber cannot grow memory without bound.
+func WithSubscriberQueue[K comparable, V any](size int, overflow OverflowPolicy) Option[K, V] {
+	if size <= 0 {
+		panic("subscriber queue size must be greater than zero")
+	}
+	return func(c *Cache[K, V]) {
+		c.subQueue = size
+		c.subOverflow = overflow
+	}
+}
+
+// Event queued for a subscriber, along with the value it concerns.
+type published[K comparable, V any] struct {
+	event Event[K]
+	value V
+}
+
+// Calls fn for every mutation of the cache, in the order the mutations happened, so secondary
+// indexes, replicas or audit logs can follow the cache without polling. Events are those recorded by
+// WithEventLog: writes, including overwrites, as OpSet; removals as OpRemove, with the reason they
+// happened (deletion, capacity eviction, expiration, Dump); soft deletes and resurrections.
+// value is the written value for OpSet, and the value of the entry otherwise.
+//
+// fn runs in a goroutine dedicated to the subscription, one event at a time, and may call back into
+// the cache. Events are queued while fn is busy, up to the bound set by WithSubscriberQueue; past it,
+// events are dropped, so fn should keep up with the write rate. cancel ends the subscription: fn is
+// not called again once the current call, if any, returns. Subscriptions also end when the cache is
+// closed.
+func (c *Cache[K, V]) Subscribe(fn func(e Event[K], value V)) (cancel func()) {
+	s := newSubscriber[K, V](c.subscriberQueue(), c.subOverflow)
+	s.fn = fn
+	c.lock()
+	c.subscribers = append(c.subscribers, s)
+	c.unlock()
+	go s.run(c.done)
+
+	return c.canceler(s, func() {
+		c.subscribers = without(c.subscribers, s)
+	})
+}
+
+// Returns a channel receiving the events of key, in the order they happened: writes, removals with
+// their reason (deletion, capacity eviction, expiration, Dump), soft deletes and resurrections, so
+// data derived from the entry can be invalidated as soon as it changes. Events are queued until
+// received, up to the bound set by WithSubscriberQueue, like those of Subscribe: once the watcher
+// falls that far behind, events are dropped according to the overflow policy and counted in
+// Stats.DroppedEvents. cancel ends the watch and closes the channel, dropping undelivered events;
+// closing the cache also closes the channel.
+func (c *Cache[K, V]) Watch(key K) (_ <-chan Event[K], cancel func()) {
+	s := newSubscriber[K, V](c.subscriberQueue(), c.subOverflow)
+	s.out = make(chan Event[K])
+	c.lock()
+	if c.watchers == nil {
+		c.watchers = make(map[K][]*subscriber[K, V])
+	}
+	c.watchers[key] = append(c.watchers[key], s)
+	c.unlock()
+	go s.run(c.done)
+
+	return s.out, c.canceler(s, func() {
+		if rest := without(c.watchers[key], s); len(rest) > 0 {
+			c.watchers[key] = rest
+		} else {
+			delete(c.watchers, key)
+		}
+	})
+}
+
+func newSubscriber[K comparable, V any](size int, overflow OverflowPolicy) *subscriber[K, V] {
+	return &subscriber[K, V]{
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		size:     size,
+		overflow: overflow,
+	}
+}
+
+// Returns the bound of the event queue of new subscriptions.
+func (c *Cache[K, V]) subscriberQueue() int {
+	if c.subQueue == 0 {
+		return defaultSubscriberQueue
+	}
+	return c.subQueue
+}
+
+// Returns an idempotent function that unregisters s with unregister, called with the cache lock held,
+// and stops its goroutine.
+func (c *Cache[K, V]) canceler(s *subscriber[K, V], unregister func()) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.lock()
+			unregister()
+			c.unlock()
+
+			s.mu.Lock()
+			s.canceled = true
+			s.queue = nil
+			s.mu.Unlock()
+			close(s.stop)
+		})
+	}
+}
+
+// Returns subs without s, in a new slice so that the original is not modified.
+func without[K comparable, V any](subs []*subscriber[K, V], s *subscriber[K, V]) []*subscriber[K, V] {
+	i := slices.Index(subs, s)
+	if i < 0 {
+		return subs
+	}
+	return slices.Concat(subs[:i], subs[i+1:])
+}
+
+// Queues an event for delivery; called with the cache lock held, so events are queued in order.
+// It reports whether an event was dropped because the queue is full.
+func (s *subscriber[K, V]) publish(e Event[K], value V) bool {
+	s.mu.Lock()
+	dropped := len(s.queue) >= s.size
+	switch {
+	case !dropped:
+		s.queue = append(s.queue, published[K, V]{event: e, value: value})
+	case s.overflow == DropOldest:
+		s.queue[0] = published[K, V]{}
+		s.queue = append(s.queue[1:], published[K, V]{event: e, value: value})
+	}
+	s.mu.Unlock()
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return dropped
+}
+
+// Delivers queued events until the subscription is canceled or the cache is closed.
+func (s *subscriber[K, V]) run(done <-chan struct{}) {
+	if s.out != nil {
+		defer close(s.out)
+	}
+	for {
+		select {
+		case <-s.wake:
+		case <-s.stop:
+			return
+		case <-done:
+			return
+		}
+		for {
+			s.mu.Lock()
+			if s.canceled || len(s.queue) == 0 {
+				s.mu.Unlock()
+				break
+			}
+			p := s.queue[0]
+			s.queue[0] = published[K, V]{}
+			s.queue = s.queue[1:]
+			s.mu.Unlock()
+
+			if s.out == nil {
+				s.fn(p.event, p.value)
+				continue
+			}
+			select {
+			case s.out <- p.event:
+			case <-s.stop:
+				return
+			case <-done:
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package goutte_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheSubscribe(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+
+	events := make(chan string, 16)
+	cancel := cache.Subscribe(func(e goutte.Event[string], value int) {
+		if e.Op == goutte.OpRemove {
+			events <- fmt.Sprintf("%s %s=%d %s", e.Op, e.Key, value, e.Reason)
+		} else {
+			events <- fmt.Sprintf("%s %s=%d", e.Op, e.Key, value)
+		}
+	})
+
+	cache.Set("a", 1)
+	cache.Set("a", 2)
+	cache.SetWithTTL("b", 3, 10*time.Millisecond)
+	cache.Set("c", 4) // evicts "a"
+	cache.Delete("c")
+	time.Sleep(30 * time.Millisecond)
+	cache.Get("b")
+
+	want := []string{
+		"set a=1",
+		"set a=2",
+		"set b=3",
+		"set c=4",
+		"remove a=2 capacity",
+		"remove c=4 deleted",
+		"remove b=3 expired",
+	}
+	var got []string
+	for range want {
+		select {
+		case e := <-events:
+			got = append(got, e)
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for events, got %q", got)
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected events %q, got %q", want, got)
+	}
+
+	cancel()
+	cache.Set("d", 5)
+	select {
+	case e := <-events:
+		t.Errorf("Expected no event after cancel, got %q", e)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestCacheSubscribeReentrant(t *testing.T) {
+	cache := goutte.NewCache[string, int](4)
+	defer cache.Close()
+
+	// A subscriber maintaining a derived entry in the same cache.
+	done := make(chan struct{})
+	cancel := cache.Subscribe(func(e goutte.Event[string], value int) {
+		if e.Op == goutte.OpSet && e.Key == "a" {
+			cache.Set("double-a", 2*value)
+			close(done)
+		}
+	})
+	defer cancel()
+
+	cache.Set("a", 21)
+	<-done
+	if val, ok := cache.Get("double-a"); !ok || val != 42 {
+		t.Errorf("Expected 'double-a' to be 42, got %v (found: %v)", val, ok)
+	}
+}
+
+func TestCacheWatch(t *testing.T) {
+	cache := goutte.NewCache[string, int](4)
+	defer cache.Close()
+
+	events, cancel := cache.Watch("a")
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Delete("a")
+	cache.SetWithTTL("a", 3, 10*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	cache.Get("a")
+
+	want := []string{"set", "remove deleted", "set", "remove expired"}
+	var got []string
+	for range want {
+		select {
+		case e := <-events:
+			if e.Key != "a" {
+				t.Errorf("Unexpected event for key %q", e.Key)
+			}
+			if e.Op == goutte.OpRemove {
+				got = append(got, fmt.Sprintf("%s %s", e.Op, e.Reason))
+			} else {
+				got = append(got, e.Op.String())
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for events, got %q", got)
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected events %q, got %q", want, got)
+	}
+
+	cancel()
+	cache.Set("a", 4)
+	if e, ok := <-events; ok {
+		t.Errorf("Expected the channel to be closed, got %+v", e)
+	}
+}
+
+func TestCacheSubscriberQueueBound(t *testing.T) {
+	for _, tc := range []struct {
+		overflow goutte.OverflowPolicy
+		want     []int
+	}{
+		{goutte.DropOldest, []int{1, 5, 6}},
+		{goutte.DropNewest, []int{1, 2, 3}},
+	} {
+		t.Run(tc.overflow.String(), func(t *testing.T) {
+			cache := goutte.NewCache(8, goutte.WithSubscriberQueue[string, int](2, tc.overflow))
+			defer cache.Close()
+
+			received := make(chan int, 8)
+			release := make(chan struct{})
+			cancel := cache.Subscribe(func(_ goutte.Event[string], value int) {
+				received <- value
+				<-release
+			})
+			defer cancel()
+
+			// The subscriber blocks on the first event while the next ones queue up.
+			cache.Set("k", 1)
+			<-received
+			for i := 2; i <= 6; i++ {
+				cache.Set("k", i)
+			}
+			if n := cache.Stats().DroppedEvents; n != 3 {
+				t.Errorf("Expected 3 dropped events, got %d", n)
+			}
+			close(release)
+
+			got := []int{1}
+			for len(got) < len(tc.want) {
+				select {
+				case v := <-received:
+					got = append(got, v)
+				case <-time.After(time.Second):
+					t.Fatalf("Timed out waiting for events, got %v", got)
+				}
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("Expected values %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCacheWatchQueueBound(t *testing.T) {
+	cache := goutte.NewCache(8, goutte.WithSubscriberQueue[string, int](2, goutte.DropOldest))
+	defer cache.Close()
+
+	ch, cancel := cache.Watch("k")
+	defer cancel()
+
+	// Nothing reads the channel while the writes happen: the watcher goroutine holds at most one event
+	// waiting to be received, and queues at most two.
+	for i := 1; i <= 10; i++ {
+		cache.Set("k", i)
+	}
+	dropped := cache.Stats().DroppedEvents
+
+	received := 0
+	for done := false; !done; {
+		select {
+		case <-ch:
+			received++
+		case <-time.After(20 * time.Millisecond):
+			done = true
+		}
+	}
+	if received > 3 || uint64(received)+dropped != 10 {
+		t.Errorf("Expected at most 3 events received and the rest dropped, got %d received and %d dropped", received, dropped)
+	}
+}
@@ -0,0 +1,96 @@
+package goutte
+
+import "time"
+
+// Hides an entry from reads for the given window while retaining it, so it can be restored with Resurrect.
+// The tombstone keeps its place in the LRU list and counts against capacity until the window elapses,
+// at which point it is removed like an expired entry. Writing the key again replaces the tombstone.
+// Until then, loaders do not re-cache the key: GetOrCompute and LoadingCache.Get return
+// ErrSoftDeleted without loading, and GetOrLease grants no lease.
+// A load or lease already running for the key, e.g. a background refresh, does not store its result.
+// A non-positive window deletes the entry immediately. It reports whether a live entry was found.
+func (c *Cache[K, V]) SoftDelete(key K, window time.Duration) bool {
+	if window <= 0 {
+		c.lock()
+		defer c.unlock()
+		c.revokeLeaseLocked(key)
+		c.invalidateFlightLocked(key)
+		if ele := c.lookupLocked(key); ele != nil {
+			c.removeElementLocked(ele, EvictionDeleted)
+			if c.migration != nil {
+				c.mirrorLocked(key)
+			}
+			return true
+		}
+		return false
+	}
+
+	deadline := c.clock.Now().Add(window)
+
+	c.lock()
+	defer c.unlock()
+
+	c.revokeLeaseLocked(key)
+	c.invalidateFlightLocked(key)
+	ele := c.lookupLocked(key)
+	if ele == nil {
+		return false
+	}
+	ent := ele.Value.(*entry[K, V])
+	ent.tombstone = true
+	c.tombstones++
+	c.logEventLocked(OpSoftDelete, key, ent.value, 0)
+	if c.migration != nil {
+		c.mirrorLocked(key)
+	}
+	ent.liveExpiration = ent.expiration
+	c.setExpirationLocked(ent, deadline)
+	return true
+}
+
+// Reports whether key is hidden by SoftDelete; lookupLocked must have been called first, so that an
+// elapsed window has removed the tombstone.
+func (c *Cache[K, V]) tombstonedLocked(key K) bool {
+	ele, ok := c.cache[key]
+	return ok && ele.Value.(*entry[K, V]).tombstone
+}
+
+// Restores an entry hidden by SoftDelete, along with its original expiration.
+// It reports false if the key is not soft-deleted, or if its original TTL elapsed in the meantime,
+// in which case the tombstone is removed.
+func (c *Cache[K, V]) Resurrect(key K) bool {
+	c.lock()
+	defer c.unlock()
+
+	ele, ok := c.cache[key]
+	if !ok {
+		return false
+	}
+	ent := ele.Value.(*entry[K, V])
+	if !ent.tombstone {
+		return false
+	}
+	now := c.clock.Now()
+	if !ent.expiration.IsZero() && now.After(ent.expiration) {
+		// The soft-delete window itself has elapsed.
+		c.removeElementLocked(ele, EvictionDeleted)
+		return false
+	}
+	if !ent.liveExpiration.IsZero() && now.After(ent.liveExpiration) {
+		ent.tombstone = false
+		c.tombstones--
+		c.removeElementLocked(ele, EvictionExpired)
+		return false
+	}
+
+	ent.tombstone = false
+	c.tombstones--
+	c.logEventLocked(OpResurrect, key, ent.value, 0)
+	c.setExpirationLocked(ent, ent.liveExpiration)
+	ent.liveExpiration = time.Time{}
+	if c.migration != nil {
+		// SoftDelete mirrored a deletion.
+		c.mirrorLocked(key)
+	}
+	return true
+}
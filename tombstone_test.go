@@ -0,0 +1,235 @@
+package goutte_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheSoftDeleteResurrect(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+	cache.Set("a", 1)
+
+	if !cache.SoftDelete("a", time.Second) {
+		t.Fatal("Expected SoftDelete to find key 'a'")
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected soft-deleted key 'a' to be hidden from Get")
+	}
+
+	if !cache.Resurrect("a") {
+		t.Fatal("Expected Resurrect to restore key 'a'")
+	}
+	if val, ok := cache.Get("a"); !ok || val != 1 {
+		t.Errorf("Expected key 'a' to have value 1 after Resurrect, got %v (found: %v)", val, ok)
+	}
+	if cache.Resurrect("a") {
+		t.Error("Expected Resurrect of a live key to report false")
+	}
+}
+
+func TestCacheSoftDeleteWindowElapses(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+	cache.Set("a", 1)
+
+	cache.SoftDelete("a", 50*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+
+	if cache.Resurrect("a") {
+		t.Error("Expected Resurrect to fail once the window elapsed")
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected key 'a' to be gone after the window elapsed")
+	}
+}
+
+func TestCacheSoftDeleteKeepsTTL(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+	cache.SetWithTTL("a", 1, 80*time.Millisecond)
+
+	cache.SoftDelete("a", time.Second)
+	cache.Resurrect("a")
+
+	// The original TTL still applies after resurrection.
+	time.Sleep(120 * time.Millisecond)
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected resurrected key 'a' to expire with its original TTL")
+	}
+}
+
+func TestCacheSoftDeleteCountsAgainstCapacity(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.SoftDelete("a", time.Second)
+
+	// The tombstone for "a" is the least recently used entry and is evicted first.
+	cache.Set("c", 3)
+	if cache.Resurrect("a") {
+		t.Error("Expected tombstone 'a' to be evicted by capacity pressure")
+	}
+	if val, ok := cache.Get("b"); !ok || val != 2 {
+		t.Errorf("Expected key 'b' to have value 2, got %v (found: %v)", val, ok)
+	}
+
+	// Writing a soft-deleted key replaces the tombstone.
+	cache.SoftDelete("b", time.Second)
+	cache.Set("b", 20)
+	if val, ok := cache.Get("b"); !ok || val != 20 {
+		t.Errorf("Expected key 'b' to have value 20, got %v (found: %v)", val, ok)
+	}
+}
+
+func TestCacheSoftDeleteStats(t *testing.T) {
+	clock := goutte.NewManualClock(time.Unix(0, 0))
+	cache := goutte.NewCache(4, goutte.WithClock[string, int](clock))
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	cache.SoftDelete("a", time.Minute)
+	cache.SoftDelete("b", time.Hour)
+
+	if stats := cache.Stats(); stats.Tombstones != 2 || stats.Size != 1 {
+		t.Errorf("Expected 2 tombstones and 1 live entry, got %+v", stats)
+	}
+	cache.Resurrect("b")
+	clock.Advance(2 * time.Minute)
+	if stats := cache.Stats(); stats.Tombstones != 0 || stats.Size != 2 {
+		t.Errorf("Expected no tombstones and 2 live entries, got %+v", stats)
+	}
+}
+
+func TestCacheSoftDeleteBlocksGetOrCompute(t *testing.T) {
+	clock := goutte.NewManualClock(time.Unix(0, 0))
+	cache := goutte.NewCache(2, goutte.WithClock[string, int](clock))
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.SoftDelete("a", time.Minute)
+
+	computed := 0
+	compute := func() (int, error) {
+		computed++
+		return 2, nil
+	}
+	if _, err := cache.GetOrCompute("a", compute); !errors.Is(err, goutte.ErrSoftDeleted) {
+		t.Errorf("Expected ErrSoftDeleted during the window, got %v", err)
+	}
+	if computed != 0 {
+		t.Errorf("Expected compute not to run during the window, ran %d times", computed)
+	}
+	if !cache.Resurrect("a") {
+		t.Error("Expected the tombstone to survive GetOrCompute")
+	}
+
+	cache.SoftDelete("a", time.Minute)
+	clock.Advance(2 * time.Minute)
+	if val, err := cache.GetOrCompute("a", compute); err != nil || val != 2 {
+		t.Errorf("Expected the key to be computed once the window elapses, got %v (err: %v)", val, err)
+	}
+}
+
+func TestCacheSoftDeleteBlocksLoadingCache(t *testing.T) {
+	clock := goutte.NewManualClock(time.Unix(0, 0))
+	loads := 0
+	cache := goutte.NewLoadingCache(goutte.NewCache(2, goutte.WithClock[string, int](clock)),
+		func(context.Context, string) (int, error) {
+			loads++
+			return 2, nil
+		})
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.SoftDelete("a", time.Minute)
+
+	if _, err := cache.Get(context.Background(), "a"); !errors.Is(err, goutte.ErrSoftDeleted) {
+		t.Errorf("Expected ErrSoftDeleted during the window, got %v", err)
+	}
+	if loads != 0 {
+		t.Errorf("Expected the loader not to run during the window, ran %d times", loads)
+	}
+
+	clock.Advance(2 * time.Minute)
+	if val, err := cache.Get(context.Background(), "a"); err != nil || val != 2 {
+		t.Errorf("Expected the key to be loaded once the window elapses, got %v (err: %v)", val, err)
+	}
+}
+
+func TestCacheSoftDeleteBlocksGetOrLease(t *testing.T) {
+	clock := goutte.NewManualClock(time.Unix(0, 0))
+	cache := goutte.NewCache(2, goutte.WithClock[string, int](clock))
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.SoftDelete("a", time.Minute)
+	if _, ok, lease := cache.GetOrLease("a"); ok || lease != nil {
+		t.Errorf("Expected a miss without lease during the window, got found=%v lease=%v", ok, lease)
+	}
+
+	clock.Advance(2 * time.Minute)
+	if _, _, lease := cache.GetOrLease("a"); lease == nil {
+		t.Error("Expected a lease once the window elapses")
+	}
+}
+
+func TestCacheSoftDeleteDuringRefresh(t *testing.T) {
+	for _, window := range []time.Duration{time.Minute, 0} {
+		t.Run(window.String(), func(t *testing.T) {
+			clock := goutte.NewManualClock(time.Unix(0, 0))
+			started := make(chan struct{})
+			release := make(chan struct{})
+			returned := make(chan struct{})
+			var loads int
+			loader := func(ctx context.Context, key string) (int, error) {
+				loads++
+				if loads == 1 {
+					return 1, nil
+				}
+				close(started)
+				<-release
+				defer close(returned)
+				return 2, nil
+			}
+			cache := goutte.NewLoadingCache(goutte.NewCache(2, goutte.WithClock[string, int](clock)), loader,
+				goutte.WithRefreshAfter[string, int](time.Second))
+			defer cache.Close()
+
+			ctx := context.Background()
+			if val, _ := cache.Get(ctx, "a"); val != 1 {
+				t.Fatalf("Expected the first load, got %d", val)
+			}
+			clock.Advance(2 * time.Second)
+			if val, _ := cache.Get(ctx, "a"); val != 1 {
+				t.Fatalf("Expected the current value while refreshing, got %d", val)
+			}
+			<-started
+			if !cache.SoftDelete("a", window) {
+				t.Fatal("Expected SoftDelete to find key 'a'")
+			}
+			close(release)
+			<-returned
+
+			// The refresh stores its result right after the loader returns, unless it went stale.
+			deadline := time.Now().Add(50 * time.Millisecond)
+			for time.Now().Before(deadline) {
+				if val, ok := cache.Peek("a"); ok {
+					t.Fatalf("Expected the refresh not to undo SoftDelete, got %d", val)
+				}
+				time.Sleep(time.Millisecond)
+			}
+			if window > 0 {
+				if !cache.Resurrect("a") {
+					t.Fatal("Expected 'a' to be resurrected")
+				}
+				if val, _ := cache.Peek("a"); val != 1 {
+					t.Errorf("Expected the value from before SoftDelete, got %d", val)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package goutte
+
+import "time"
+
+// Conditions under which a cache is considered warm; see WithWarmup.
+// The cache becomes ready as soon as any enabled condition holds, or when MarkReady is called,
+// and stays ready from then on.
+type Warmup struct {
+	MinAge      time.Duration // ready once the cache is at least this old; 0 disables
+	MinHitRatio float64       // ready once the hit ratio reaches this value; 0 disables
+	MinLookups  uint64        // lookups required before the hit ratio is considered
+
+	// Skips recency and diagnostic bookkeeping on lookups (LRU promotion, rank histogram, key stats,
+	// miss log) until the cache is ready, reducing startup overhead while the cache is mostly missing.
+	Passthrough bool
+}
+
+type warmupState struct {
+	Warmup
+	created time.Time
+	ready   bool
+}
+
+// Tracks whether the cache is warm, as reported by Ready; see Warmup for the conditions.
+func WithWarmup[K comparable, V any](w Warmup) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.warmup = &warmupState{Warmup: w}
+	}
+}
+
+// Reports whether the cache is warm, e.g. for load balancer weighting.
+// A cache constructed without WithWarmup is always ready.
+func (c *Cache[K, V]) Ready() bool {
+	c.lock()
+	defer c.unlock()
+
+	return c.readyLocked()
+}
+
+// Marks the cache as warm, e.g. once an explicit warmup procedure completes.
+func (c *Cache[K, V]) MarkReady() {
+	c.lock()
+	defer c.unlock()
+
+	if c.warmup != nil {
+		c.warmup.ready = true
+	}
+}
+
+func (c *Cache[K, V]) readyLocked() bool {
+	w := c.warmup
+	if w == nil || w.ready {
+		return true
+	}
+	lookups := c.hits + c.misses
+	switch {
+	case w.MinAge > 0 && c.clock.Now().Sub(w.created) >= w.MinAge:
+		w.ready = true
+	case w.MinHitRatio > 0 && lookups > 0 && lookups >= w.MinLookups &&
+		float64(c.hits)/float64(lookups) >= w.MinHitRatio:
+		w.ready = true
+	}
+	return w.ready
+}
+
+// Reports whether lookup bookkeeping should be skipped because the cache is still warming up.
+func (c *Cache[K, V]) passthroughLocked() bool {
+	return c.warmup != nil && c.warmup.Passthrough && !c.readyLocked()
+}
@@ -0,0 +1,76 @@
+package goutte_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheReadyByDefault(t *testing.T) {
+	cache := goutte.NewCache[string, int](2)
+	defer cache.Close()
+
+	if !cache.Ready() {
+		t.Error("Expected a cache without warmup conditions to be ready")
+	}
+}
+
+func TestCacheReadyByAge(t *testing.T) {
+	cache := goutte.NewCache[string, int](2, goutte.WithWarmup[string, int](goutte.Warmup{MinAge: 30 * time.Millisecond}))
+	defer cache.Close()
+
+	if cache.Ready() {
+		t.Error("Expected a new cache not to be ready")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if !cache.Ready() {
+		t.Error("Expected the cache to be ready after MinAge")
+	}
+}
+
+func TestCacheReadyByHitRatio(t *testing.T) {
+	cache := goutte.NewCache[string, int](2, goutte.WithWarmup[string, int](goutte.Warmup{MinHitRatio: 0.5, MinLookups: 4}))
+	defer cache.Close()
+	cache.Set("a", 1)
+
+	cache.Get("a")
+	cache.Get("a")
+	if cache.Ready() {
+		t.Error("Expected the cache not to be ready before MinLookups")
+	}
+	cache.Get("b")
+	cache.Get("a")
+	if !cache.Ready() {
+		t.Error("Expected the cache to be ready with a 3/4 hit ratio")
+	}
+
+	// Readiness is sticky.
+	for i := 0; i < 10; i++ {
+		cache.Get("missing")
+	}
+	if !cache.Ready() {
+		t.Error("Expected the cache to stay ready")
+	}
+}
+
+func TestCacheWarmupPassthrough(t *testing.T) {
+	cache := goutte.NewCache[string, int](2, goutte.WithWarmup[string, int](goutte.Warmup{MinAge: time.Hour, Passthrough: true}))
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+
+	// While warming up, hits do not promote, so "a" stays least recently used.
+	cache.Get("a")
+	cache.Set("c", 3)
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected key 'a' to be evicted while promotion is bypassed")
+	}
+
+	cache.MarkReady()
+	cache.Get("b")
+	cache.Set("d", 4)
+	if _, ok := cache.Get("b"); !ok {
+		t.Error("Expected key 'b' to be promoted once the cache is ready")
+	}
+}
@@ -0,0 +1,66 @@
+package goutte
+
+import "time"
+
+// Bounds the cache by the total weight of its entries, as computed by weigh, in addition to the
+// entry count given to NewCache: after each write, least recently used entries are evicted until the
+// total weight is at most maxWeight. Use it when values vary widely in size, e.g. with weigh returning
+// the length of a byte slice; pass NewCache a capacity large enough not to be reached first.
+// weigh is called with the cache lock held, on every write; negative weights count as zero.
+// An entry heavier than maxWeight on its own is evicted as soon as it is written.
+//
+// weigh may be nil if costs are given with SetWithCost, in which case entries written otherwise weigh 1.
+func WithWeigher[K comparable, V any](maxWeight int64, weigh func(key K, value V) int64) Option[K, V] {
+	if maxWeight <= 0 {
+		panic("maximum weight must be greater than zero")
+	}
+	return func(c *Cache[K, V]) {
+		c.maxWeight = maxWeight
+		c.weigh = weigh
+	}
+}
+
+// Inserts or updates a key-value pair with an optional TTL, like SetWithTTL, counting cost against
+// the maximum weight set by WithWeigher instead of the weight computed by the weigher. Use it when
+// the caller already knows the size of the value, e.g. the length of its serialized form, and the
+// weigher could not compute it cheaply. A negative cost counts as zero. The cost is kept until the key
+// is written again, except by Replace. Without WithWeigher, costs are reported in Stats.Weight but not
+// limited.
+func (c *Cache[K, V]) SetWithCost(key K, value V, cost int64, ttl time.Duration) {
+	c.lock()
+	defer c.unlock()
+
+	c.writeCostLocked(key, value, ttl, max(cost, 0))
+}
+
+// Like Replace, replacing the cost of the entry with cost; see SetWithCost.
+func (c *Cache[K, V]) ReplaceWithCost(key K, value V, cost int64) bool {
+	c.lock()
+	defer c.unlock()
+
+	return c.replaceLocked(key, value, max(cost, 0))
+}
+
+// Sets the weight of ent after its value changed: cost if it is not negative, otherwise the weight
+// computed by the weigher, or 1 if there is none but a maximum weight is set.
+func (c *Cache[K, V]) weighLocked(ent *entry[K, V], cost int64) {
+	var w int64
+	switch {
+	case cost >= 0:
+		w = cost
+	case c.weigh != nil:
+		w = max(c.weigh(ent.key, ent.value), 0)
+	case c.maxWeight > 0:
+		w = 1
+	}
+	ent.costed = cost >= 0
+	c.weight += w - ent.weight
+	ent.weight = w
+}
+
+// Evicts least recently used entries until the cache fits its capacity and maximum weight.
+func (c *Cache[K, V]) evictLocked() {
+	for c.ll.Len() > c.capacity || (c.maxWeight > 0 && c.weight > c.maxWeight && c.ll.Len() > 0) {
+		c.removeOldestLocked()
+	}
+}
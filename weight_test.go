@@ -0,0 +1,98 @@
+package goutte_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheWeigher(t *testing.T) {
+	cache := goutte.NewCache(100, goutte.WithWeigher(10, func(key string, value []byte) int64 {
+		return int64(len(value))
+	}))
+	defer cache.Close()
+
+	cache.Set("a", make([]byte, 4))
+	cache.Set("b", make([]byte, 4))
+	cache.Get("a")
+	cache.Set("c", make([]byte, 4)) // evicts "b", the least recently used
+	if cache.Contains("b") || !cache.Contains("a") || !cache.Contains("c") {
+		t.Error("Expected 'b' to be evicted to fit the maximum weight")
+	}
+	if w := cache.Stats().Weight; w != 8 {
+		t.Errorf("Expected a total weight of 8, got %d", w)
+	}
+
+	// Growing an entry evicts others.
+	cache.Set("c", make([]byte, 9))
+	if cache.Contains("a") || !cache.Contains("c") {
+		t.Error("Expected 'a' to be evicted when 'c' grew")
+	}
+
+	// An entry heavier than the maximum is not retained.
+	cache.Set("huge", make([]byte, 11))
+	if cache.Len() != 0 {
+		t.Errorf("Expected an empty cache, got %d entries", cache.Len())
+	}
+
+	cache.Set("d", make([]byte, 2))
+	cache.Delete("d")
+	if w := cache.Stats().Weight; w != 0 {
+		t.Errorf("Expected a total weight of 0, got %d", w)
+	}
+}
+
+func TestCacheSetWithCost(t *testing.T) {
+	cache := goutte.NewCache(100, goutte.WithWeigher[string, string](10, nil))
+	defer cache.Close()
+
+	cache.SetWithCost("a", "x", 6, 0)
+	cache.Set("b", "y") // weighs 1
+	cache.SetWithCost("c", "z", 4, time.Minute)
+	if cache.Contains("a") || !cache.Contains("b") || !cache.Contains("c") {
+		t.Error("Expected 'a' to be evicted to fit the maximum weight")
+	}
+	if w := cache.Stats().Weight; w != 5 {
+		t.Errorf("Expected a total weight of 5, got %d", w)
+	}
+
+	// Writing the key again drops the explicit cost.
+	cache.Set("c", "z")
+	if w := cache.Stats().Weight; w != 2 {
+		t.Errorf("Expected a total weight of 2, got %d", w)
+	}
+}
+
+func TestCacheReplaceKeepsCost(t *testing.T) {
+	cache := goutte.NewCache(100, goutte.WithWeigher[string, string](10, nil))
+	defer cache.Close()
+
+	cache.SetWithCost("a", "x", 6, 0)
+	if !cache.Replace("a", "y") {
+		t.Fatal("Expected Replace to update 'a'")
+	}
+	if w := cache.Stats().Weight; w != 6 {
+		t.Errorf("Expected Replace to keep the cost of 6, got %d", w)
+	}
+
+	if !cache.ReplaceWithCost("a", "z", 3) {
+		t.Fatal("Expected ReplaceWithCost to update 'a'")
+	}
+	if w := cache.Stats().Weight; w != 3 {
+		t.Errorf("Expected a total weight of 3, got %d", w)
+	}
+	if val, _ := cache.Get("a"); val != "z" {
+		t.Errorf("Expected 'z', got %q", val)
+	}
+
+	// Entries without an explicit cost are weighed again.
+	cache.Set("b", "y")
+	cache.Replace("b", "w")
+	if w := cache.Stats().Weight; w != 4 {
+		t.Errorf("Expected a total weight of 4, got %d", w)
+	}
+	if cache.ReplaceWithCost("c", "v", 1) || cache.Contains("c") {
+		t.Error("Expected ReplaceWithCost not to insert a missing key")
+	}
+}
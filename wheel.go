@@ -0,0 +1,227 @@
+package goutte
+
+import (
+	"container/list"
+	"math/bits"
+	"time"
+)
+
+const (
+	wheelBits   = 6
+	wheelSlots  = 1 << wheelBits
+	wheelLevels = 6 // 2^36 ticks: over two years at a millisecond tick
+)
+
+// Hierarchical timing wheel (Varghese and Lauck), an alternative to the expiration heap; see
+// WithTimingWheel. Level l has wheelSlots slots spanning wheelSlots^l ticks each; a timer sits in the
+// lowest level whose span covers its distance to now, and cascades down to finer levels as time
+// advances, until a slot of level 0 fires and moves it to the due list.
+type timingWheel[K comparable] struct {
+	tick   time.Duration
+	start  time.Time
+	now    int64 // last tick processed
+	wake   int64 // tick at which the expiration goroutine plans to wake up
+	levels [wheelLevels][wheelSlots]list.List
+	counts [wheelLevels]int // timers per level
+	due    list.List        // fired timers, awaiting removal of their entry
+}
+
+// Pending expiration of an entry in the timing wheel.
+type wheelTimer[K comparable] struct {
+	key      K
+	deadline int64         // first tick at or after the expiration
+	level    int           // level holding the timer, or -1 once due
+	slot     *list.List    // list holding the timer; nil once canceled or expired
+	ele      *list.Element // element of the timer in slot
+}
+
+// Tracks TTLs with a hierarchical timing wheel instead of a min-heap, so scheduling, rescheduling and
+// canceling an expiration is O(1) rather than O(log n), e.g. for caches holding millions of entries
+// with TTLs, or rewritten under heavy SetWithTTL traffic, or with sliding TTLs renewed by every hit.
+// The expiration goroutine also wakes up less often: it is only signaled by writes expiring before
+// its next planned wake-up.
+//
+// Expired entries are removed up to one tick late, so tick trades timer precision for wheel work per
+// unit of time. Reads still check expirations exactly, and never return an expired entry.
+func WithTimingWheel[K comparable, V any](tick time.Duration) Option[K, V] {
+	if tick <= 0 {
+		panic("timing wheel tick must be greater than zero")
+	}
+	return func(c *Cache[K, V]) {
+		c.wheel = &timingWheel[K]{tick: tick, wake: -1}
+	}
+}
+
+// Returns the number of ticks elapsed from the start of the wheel to t, rounded down, or up if ceil is
+// set.
+func (w *timingWheel[K]) ticks(t time.Time, ceil bool) int64 {
+	d := t.Sub(w.start)
+	n := int64(d / w.tick)
+	if ceil && d%w.tick > 0 {
+		n++
+	}
+	return n
+}
+
+// Schedules the expiration of key, reusing t if it is not nil, and returns the timer.
+// It reports whether the expiration goroutine must be woken up because it fires before its next wake-up.
+func (w *timingWheel[K]) schedule(t *wheelTimer[K], key K, expiration time.Time) (*wheelTimer[K], bool) {
+	if t == nil {
+		t = &wheelTimer[K]{key: key}
+	} else {
+		w.cancel(t)
+	}
+	t.deadline = w.ticks(expiration, true)
+	w.place(t)
+	return t, t.level < 0 || w.wake < 0 || t.deadline < w.wake
+}
+
+// Links t in the slot covering its deadline, or in the due list if it has passed.
+func (w *timingWheel[K]) place(t *wheelTimer[K]) {
+	delta := t.deadline - w.now
+	if delta <= 0 {
+		t.level = -1
+		t.slot = &w.due
+		t.ele = w.due.PushBack(t)
+		return
+	}
+	deadline := t.deadline
+	level := (bits.Len64(uint64(delta)) - 1) / wheelBits
+	if level >= wheelLevels {
+		// Beyond the range of the wheel: park the timer in the last slot of the top level, from which
+		// it cascades back up until its deadline is in range.
+		level = wheelLevels - 1
+		deadline = w.now + 1<<(wheelBits*wheelLevels) - 1
+	}
+	t.level = level
+	t.slot = &w.levels[level][(deadline>>(wheelBits*level))&(wheelSlots-1)]
+	t.ele = t.slot.PushBack(t)
+	w.counts[level]++
+}
+
+// Unlinks t from the wheel, if it is still scheduled.
+func (w *timingWheel[K]) cancel(t *wheelTimer[K]) {
+	if t.slot == nil {
+		return
+	}
+	t.slot.Remove(t.ele)
+	if t.level >= 0 {
+		w.counts[t.level]--
+	}
+	t.slot, t.ele = nil, nil
+}
+
+// Processes every tick up to target: slots of higher levels cascade into finer ones at their
+// boundaries, and timers in the slots of level 0 become due. Ticks at which nothing can happen, because
+// the finer levels are empty, are skipped.
+func (w *timingWheel[K]) advance(target int64) {
+	for w.now < target {
+		k := 0
+		for k < wheelLevels && w.counts[k] == 0 {
+			k++
+		}
+		if k == wheelLevels {
+			w.now = target
+			return
+		}
+		// With levels below k empty, the next event is the next boundary of level k.
+		span := int64(1) << (wheelBits * k)
+		next := (w.now/span + 1) * span
+		if next > target {
+			w.now = target
+			return
+		}
+		w.now = next
+		for level := wheelLevels - 1; level > 0; level-- {
+			if next&(1<<(wheelBits*level)-1) == 0 {
+				w.cascade(&w.levels[level][(next>>(wheelBits*level))&(wheelSlots-1)])
+			}
+		}
+		w.cascade(&w.levels[0][next&(wheelSlots-1)])
+	}
+}
+
+// Moves the timers of slot to the slots covering their deadlines from now, or to the due list.
+func (w *timingWheel[K]) cascade(slot *list.List) {
+	for ele := slot.Front(); ele != nil; ele = slot.Front() {
+		t := ele.Value.(*wheelTimer[K])
+		w.cancel(t)
+		w.place(t)
+	}
+}
+
+// Returns the tick of the next event of the wheel: now if timers are due, the next boundary of the
+// finest level holding timers otherwise, or -1 if the wheel is empty.
+func (w *timingWheel[K]) next() int64 {
+	if w.due.Len() > 0 {
+		return w.now
+	}
+	if w.counts[0] > 0 {
+		for tick := w.now + 1; ; tick++ {
+			if w.levels[0][tick&(wheelSlots-1)].Len() > 0 {
+				return tick
+			}
+		}
+	}
+	for level := 1; level < wheelLevels; level++ {
+		if w.counts[level] > 0 {
+			span := int64(1) << (wheelBits * level)
+			return (w.now/span + 1) * span
+		}
+	}
+	return -1
+}
+
+// Returns the number of scheduled timers, including due ones.
+func (w *timingWheel[K]) len() int {
+	n := w.due.Len()
+	for _, count := range w.counts {
+		n += count
+	}
+	return n
+}
+
+// Drops every timer, once the entries are cleared.
+func (w *timingWheel[K]) reset() {
+	for level := range w.levels {
+		for slot := range w.levels[level] {
+			w.levels[level][slot].Init()
+		}
+	}
+	w.counts = [wheelLevels]int{}
+	w.due.Init()
+}
+
+// Removes up to limit entries whose timers are due at now (all of them if limit is 0), and returns the
+// number of entries removed. Like expireDueLocked, it does not split entries sharing a deadline.
+func (c *Cache[K, V]) expireWheelLocked(now time.Time, limit int) int {
+	w := c.wheel
+	w.advance(w.ticks(now, false))
+	removed := 0
+	var last time.Time // deadline of the last entry removed
+	for w.due.Len() > 0 {
+		t := w.due.Front().Value.(*wheelTimer[K])
+		ele := c.cache[t.key]
+		ent := ele.Value.(*entry[K, V])
+		if limit > 0 && removed >= limit && !ent.expiration.Equal(last) {
+			break
+		}
+		w.cancel(t)
+		ent.timer = nil
+		last = ent.expiration
+		c.removeElementLocked(ele, EvictionExpired)
+		removed++
+	}
+	return removed
+}
+
+// Returns how long the expiration goroutine may sleep before the wheel has work to do, and records
+// the planned wake-up so that only writes expiring earlier signal it.
+func (c *Cache[K, V]) wheelWaitLocked(now time.Time) time.Duration {
+	w := c.wheel
+	w.wake = w.next()
+	if w.wake < 0 {
+		return time.Hour
+	}
+	return max(0, w.start.Add(time.Duration(w.wake)*w.tick).Sub(now))
+}
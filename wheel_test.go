@@ -0,0 +1,78 @@
+package goutte_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+)
+
+func TestCacheTimingWheel(t *testing.T) {
+	expired := make(chan string, 8)
+	cache := goutte.NewCache(8,
+		goutte.WithTimingWheel[string, int](time.Millisecond),
+		goutte.WithOnRemoval(func(key string, _ int, reason goutte.EvictionReason) {
+			if reason == goutte.EvictionExpired {
+				expired <- key
+			}
+		}))
+	defer cache.Close()
+
+	cache.SetWithTTL("short", 1, 20*time.Millisecond)
+	cache.SetWithTTL("long", 2, 150*time.Millisecond) // beyond the first level
+	cache.SetWithTTL("renewed", 3, 20*time.Millisecond)
+	cache.SetWithTTL("renewed", 3, time.Hour)
+	cache.SetWithTTL("persisted", 4, 20*time.Millisecond)
+	cache.Persist("persisted")
+	cache.SetWithTTL("deleted", 5, 20*time.Millisecond)
+	cache.Delete("deleted")
+
+	if n := cache.JanitorStats().Pending; n != 3 {
+		t.Errorf("Expected 3 pending timers, got %d", n)
+	}
+	if err := cache.Verify(); err != nil {
+		t.Error(err)
+	}
+
+	for _, want := range []string{"short", "long"} {
+		select {
+		case key := <-expired:
+			if key != want {
+				t.Errorf("Expected key %q to expire, got %q", want, key)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected key %q to expire", want)
+		}
+	}
+	if n := cache.Len(); n != 2 {
+		t.Errorf("Expected 2 entries left, got %d", n)
+	}
+	if err := cache.Verify(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCacheTimingWheelRange(t *testing.T) {
+	// With a nanosecond tick, the wheel covers about a minute.
+	cache := goutte.NewCache(1024, goutte.WithTimingWheel[string, int](time.Nanosecond))
+	defer cache.Close()
+
+	for i := range 1000 {
+		cache.SetWithTTL(fmt.Sprint(i), i, time.Duration(i+1)*time.Minute/10)
+	}
+	if n := cache.JanitorStats().Pending; n != 1000 {
+		t.Errorf("Expected 1000 pending timers, got %d", n)
+	}
+	if n := cache.DeleteExpired(); n != 0 {
+		t.Errorf("Expected no entry to expire, got %d", n)
+	}
+	if err := cache.Verify(); err != nil {
+		t.Error(err)
+	}
+
+	cache.Dump()
+	if n := cache.JanitorStats().Pending; n != 0 {
+		t.Errorf("Expected no pending timers after Dump, got %d", n)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/shellkah/goutte/yamlgoutte
+
+go 1.24.0
+
+replace github.com/shellkah/goutte => ../
+
+require (
+	github.com/shellkah/goutte v0.0.0-00010101000000-000000000000
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,39 @@
+// Package yamlgoutte loads goutte cache configurations from YAML.
+//
+// It lives in its own module so that goutte itself stays free of dependencies. Keys are the JSON
+// names of the goutte.Config fields, and durations use time.ParseDuration syntax:
+//
+//	capacity: 1000
+//	promotion: every:2
+//	default_ttl: 5m
+//
+//	cfg, err := yamlgoutte.LoadConfig(f)
+//	cache, err := goutte.NewFromConfig[string, User](cfg)
+package yamlgoutte
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/shellkah/goutte"
+	"gopkg.in/yaml.v3"
+)
+
+// Decodes a YAML Config from r, with the same rules as goutte.LoadConfigJSON: unknown fields are
+// rejected so typos do not go unnoticed, and the result is validated.
+func LoadConfig(r io.Reader) (goutte.Config, error) {
+	var doc map[string]any
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return goutte.Config{}, fmt.Errorf("goutte: decoding config: %w", err)
+	}
+	// The YAML document is converted to JSON so that field names, durations and validation are
+	// handled by goutte itself.
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return goutte.Config{}, fmt.Errorf("goutte: decoding config: %w", err)
+	}
+	return goutte.LoadConfigJSON(bytes.NewReader(b))
+}
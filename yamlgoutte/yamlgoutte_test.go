@@ -0,0 +1,56 @@
+package yamlgoutte_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shellkah/goutte"
+	"github.com/shellkah/goutte/yamlgoutte"
+)
+
+func TestLoadConfig(t *testing.T) {
+	cfg, err := yamlgoutte.LoadConfig(strings.NewReader(`
+# Tuned for the profile service.
+capacity: 64
+promotion: every:2
+policy: slru:0.5
+max_weight: 4096
+default_ttl: 5m
+min_residency: 1500ms
+rank_histogram: true
+event_log: 8
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := goutte.Config{
+		Capacity:      64,
+		Promotion:     "every:2",
+		Policy:        "slru:0.5",
+		MaxWeight:     4096,
+		DefaultTTL:    goutte.Duration(5 * time.Minute),
+		MinResidency:  goutte.Duration(1500 * time.Millisecond),
+		RankHistogram: true,
+		EventLog:      8,
+	}
+	if cfg != want {
+		t.Errorf("Expected %+v, got %+v", want, cfg)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	if _, err := yamlgoutte.LoadConfig(strings.NewReader("capacity: 1\ncapacty: 2\n")); err == nil {
+		t.Error("Expected unknown fields to be rejected")
+	}
+	if _, err := yamlgoutte.LoadConfig(strings.NewReader("capacity: [1\n")); err == nil {
+		t.Error("Expected malformed YAML to be rejected")
+	}
+	if _, err := yamlgoutte.LoadConfig(strings.NewReader("default_ttl: 1m\n")); !errors.Is(err, goutte.ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig without a capacity, got %v", err)
+	}
+	if _, err := yamlgoutte.LoadConfig(strings.NewReader("")); !errors.Is(err, goutte.ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig for an empty document, got %v", err)
+	}
+}